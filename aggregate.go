@@ -0,0 +1,144 @@
+package dac
+
+import (
+	"errors"
+	"math"
+)
+
+// SumU64 returns the sum of the uint64 values in the index range [lo, hi).
+// The sum wraps around on overflow. Only the values in the range are decoded.
+func (d *Dict) SumU64(lo, hi int) (sum uint64, err error) {
+	err = d.each(lo, hi, func(v uint64) {
+		sum += v
+	})
+	return
+}
+
+// MinU64 returns the smallest uint64 value in the index range [lo, hi).
+func (d *Dict) MinU64(lo, hi int) (min uint64, err error) {
+	min = math.MaxUint64
+	err = d.eachNonEmpty(lo, hi, func(v uint64) {
+		if v < min {
+			min = v
+		}
+	})
+	return
+}
+
+// MaxU64 returns the largest uint64 value in the index range [lo, hi).
+func (d *Dict) MaxU64(lo, hi int) (max uint64, err error) {
+	err = d.eachNonEmpty(lo, hi, func(v uint64) {
+		if v > max {
+			max = v
+		}
+	})
+	return
+}
+
+// SumI64 returns the sum of the int64 values in the index range [lo, hi).
+// The sum wraps around on overflow.
+func (d *Dict) SumI64(lo, hi int) (sum int64, err error) {
+	err = d.each(lo, hi, func(uv uint64) {
+		sum += unzigzag(uv)
+	})
+	return
+}
+
+// MinI64 returns the smallest int64 value in the index range [lo, hi).
+func (d *Dict) MinI64(lo, hi int) (min int64, err error) {
+	min = math.MaxInt64
+	err = d.eachNonEmpty(lo, hi, func(uv uint64) {
+		if v := unzigzag(uv); v < min {
+			min = v
+		}
+	})
+	return
+}
+
+// MaxI64 returns the largest int64 value in the index range [lo, hi).
+func (d *Dict) MaxI64(lo, hi int) (max int64, err error) {
+	max = math.MinInt64
+	err = d.eachNonEmpty(lo, hi, func(uv uint64) {
+		if v := unzigzag(uv); v > max {
+			max = v
+		}
+	})
+	return
+}
+
+// SumFloat64 returns the sum of the float64 values in the index range
+// [lo, hi).
+func (d *Dict) SumFloat64(lo, hi int) (sum float64, err error) {
+	err = d.each(lo, hi, func(uv uint64) {
+		sum += unreverse64(uv)
+	})
+	return
+}
+
+// MinFloat64 returns the smallest float64 value in the index range [lo, hi).
+// Like math.Min, MinFloat64 returns NaN when the range holds a NaN.
+func (d *Dict) MinFloat64(lo, hi int) (min float64, err error) {
+	min = math.Inf(1)
+	err = d.eachNonEmpty(lo, hi, func(uv uint64) {
+		min = math.Min(min, unreverse64(uv))
+	})
+	return
+}
+
+// MaxFloat64 returns the largest float64 value in the index range [lo, hi).
+// Like math.Max, MaxFloat64 returns NaN when the range holds a NaN.
+func (d *Dict) MaxFloat64(lo, hi int) (max float64, err error) {
+	max = math.Inf(-1)
+	err = d.eachNonEmpty(lo, hi, func(uv uint64) {
+		max = math.Max(max, unreverse64(uv))
+	})
+	return
+}
+
+// CountBool returns the number of true values in the index range [lo, hi).
+func (d *Dict) CountBool(lo, hi int) (count int, err error) {
+	if err := checkRange(lo, hi, len(d.chunks[0])); err != nil {
+		return 0, err
+	}
+
+	for _, c := range d.chunks[0][lo:hi] {
+		if c != 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// each calls f for every value in the index range [lo, hi), in order. The
+// decoding starts from the ranks at index lo, like Iterator.Value does.
+func (d *Dict) each(lo, hi int, f func(v uint64)) error {
+	if err := checkRange(lo, hi, len(d.chunks[0])); err != nil {
+		return err
+	}
+	if lo == hi {
+		return nil
+	}
+
+	ranks := d.ranksAt(lo)
+	for k := lo; k < hi; k++ {
+		f(d.decode(k, &ranks))
+	}
+	return nil
+}
+
+// checkRange returns an error when [lo, hi) is not an index range of a
+// dictionary of n entries.
+func checkRange(lo, hi, n int) error {
+	if lo < 0 || hi < lo || n < hi {
+		return errors.New("dac: index range is out of bounds")
+	}
+	return nil
+}
+
+// eachNonEmpty is like each, but returns an error for an empty range.
+func (d *Dict) eachNonEmpty(lo, hi int, f func(v uint64)) error {
+	if lo == hi {
+		return errors.New("dac: index range is empty")
+	}
+	return d.each(lo, hi, f)
+}
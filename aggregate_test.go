@@ -0,0 +1,170 @@
+package dac
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var aggregateRanges = [][2]int{{0, 1}, {0, 2_000}, {1, 64}, {63, 700}, {511, 1_537}, {1_999, 2_000}}
+
+func TestAggregateU64(t *testing.T) {
+	numbers := zipfNumbers(2_000, 15)
+	d := From(numbers)
+
+	for _, r := range aggregateRanges {
+		var sum uint64
+		min, max := uint64(math.MaxUint64), uint64(0)
+		for _, v := range numbers[r[0]:r[1]] {
+			sum += v
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+
+		if got, err := d.SumU64(r[0], r[1]); err != nil || got != sum {
+			t.Errorf("SumU64%v - got: %d, want: %d, err: %v\n", r, got, sum, err)
+		}
+		if got, err := d.MinU64(r[0], r[1]); err != nil || got != min {
+			t.Errorf("MinU64%v - got: %d, want: %d, err: %v\n", r, got, min, err)
+		}
+		if got, err := d.MaxU64(r[0], r[1]); err != nil || got != max {
+			t.Errorf("MaxU64%v - got: %d, want: %d, err: %v\n", r, got, max, err)
+		}
+	}
+
+	if sum, err := d.SumU64(5, 5); err != nil || sum != 0 {
+		t.Errorf("SumU64 of empty range - got: %d, err: %v\n", sum, err)
+	}
+	if _, err := d.MinU64(5, 5); err == nil {
+		t.Error("expected error for empty range")
+	}
+	if _, err := d.MaxU64(0, 2_001); err == nil {
+		t.Error("expected error for range beyond length")
+	}
+}
+
+func TestAggregateI64(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	numbers := make([]int64, 2_000)
+	for i := range numbers {
+		numbers[i] = r.Int63() >> uint(r.Intn(63))
+		if r.Intn(2) == 1 {
+			numbers[i] = -numbers[i]
+		}
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.WriteI64List(numbers)
+	d.Close()
+
+	for _, rg := range aggregateRanges {
+		var sum int64
+		min, max := int64(math.MaxInt64), int64(math.MinInt64)
+		for _, v := range numbers[rg[0]:rg[1]] {
+			sum += v
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+
+		if got, err := d.SumI64(rg[0], rg[1]); err != nil || got != sum {
+			t.Errorf("SumI64%v - got: %d, want: %d, err: %v\n", rg, got, sum, err)
+		}
+		if got, err := d.MinI64(rg[0], rg[1]); err != nil || got != min {
+			t.Errorf("MinI64%v - got: %d, want: %d, err: %v\n", rg, got, min, err)
+		}
+		if got, err := d.MaxI64(rg[0], rg[1]); err != nil || got != max {
+			t.Errorf("MaxI64%v - got: %d, want: %d, err: %v\n", rg, got, max, err)
+		}
+	}
+}
+
+func TestAggregateFloat64(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	numbers := make([]float64, 2_000)
+	for i := range numbers {
+		numbers[i] = r.NormFloat64() * 1e3
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.WriteFloat64List(numbers)
+	d.Close()
+
+	for _, rg := range aggregateRanges {
+		var sum float64
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, v := range numbers[rg[0]:rg[1]] {
+			sum += v
+			min, max = math.Min(min, v), math.Max(max, v)
+		}
+
+		if got, err := d.SumFloat64(rg[0], rg[1]); err != nil || got != sum {
+			t.Errorf("SumFloat64%v - got: %v, want: %v, err: %v\n", rg, got, sum, err)
+		}
+		if got, err := d.MinFloat64(rg[0], rg[1]); err != nil || got != min {
+			t.Errorf("MinFloat64%v - got: %v, want: %v, err: %v\n", rg, got, min, err)
+		}
+		if got, err := d.MaxFloat64(rg[0], rg[1]); err != nil || got != max {
+			t.Errorf("MaxFloat64%v - got: %v, want: %v, err: %v\n", rg, got, max, err)
+		}
+	}
+
+	d.WriteFloat64(math.NaN())
+	d.Close()
+	if got, err := d.MinFloat64(0, Len(d)); err != nil || !math.IsNaN(got) {
+		t.Errorf("MinFloat64 with NaN - got: %v, err: %v\n", got, err)
+	}
+}
+
+func TestCountBool(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	numbers := make([]bool, 2_000)
+	for i := range numbers {
+		numbers[i] = r.Intn(3) == 0
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.WriteBoolList(numbers)
+
+	for _, rg := range aggregateRanges {
+		var want int
+		for _, v := range numbers[rg[0]:rg[1]] {
+			if v {
+				want++
+			}
+		}
+
+		if got, err := d.CountBool(rg[0], rg[1]); err != nil || got != want {
+			t.Errorf("CountBool%v - got: %d, want: %d, err: %v\n", rg, got, want, err)
+		}
+	}
+}
+
+func BenchmarkSumU64(b *testing.B) {
+	const n = 1_000
+
+	d := From(zipfNumbers(n, 15))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.SumU64(n/4, 3*n/4)
+	}
+}
@@ -0,0 +1,102 @@
+package dac
+
+import "math/bits"
+
+// Bitmap is a set of indexes in [0, Len), stored as one bit per index. It is
+// the result of the Filter functions of a dictionary.
+type Bitmap struct {
+	words []uint64
+	n     int
+}
+
+// NewBitmap creates an empty bitmap for the indexes in [0, n).
+func NewBitmap(n int) *Bitmap {
+	if n < 0 {
+		panic("dac: bitmap length cannot be negative")
+	}
+	return &Bitmap{words: make([]uint64, (n+63)>>6), n: n}
+}
+
+// Len returns the number of indexes the bitmap can hold.
+func (b *Bitmap) Len() int {
+	return b.n
+}
+
+// Set adds index i to the bitmap.
+func (b *Bitmap) Set(i int) {
+	b.check(i)
+	b.words[i>>6] |= 1 << (i & 63)
+}
+
+// Clear removes index i from the bitmap.
+func (b *Bitmap) Clear(i int) {
+	b.check(i)
+	b.words[i>>6] &^= 1 << (i & 63)
+}
+
+// Contains reports whether index i is in the bitmap.
+func (b *Bitmap) Contains(i int) bool {
+	b.check(i)
+	return b.words[i>>6]&(1<<(i&63)) != 0
+}
+
+// Count returns the number of indexes in the bitmap.
+func (b *Bitmap) Count() (count int) {
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return
+}
+
+// And keeps the indexes that are in other as well. Both bitmaps must have
+// the same length.
+func (b *Bitmap) And(other *Bitmap) {
+	b.same(other)
+	for i, w := range other.words {
+		b.words[i] &= w
+	}
+}
+
+// Or adds the indexes of other. Both bitmaps must have the same length.
+func (b *Bitmap) Or(other *Bitmap) {
+	b.same(other)
+	for i, w := range other.words {
+		b.words[i] |= w
+	}
+}
+
+// AndNot removes the indexes of other. Both bitmaps must have the same
+// length.
+func (b *Bitmap) AndNot(other *Bitmap) {
+	b.same(other)
+	for i, w := range other.words {
+		b.words[i] &^= w
+	}
+}
+
+// Iterate calls f with every index in the bitmap, in ascending order, until f
+// returns false.
+func (b *Bitmap) Iterate(f func(idx int) bool) {
+	for i, w := range b.words {
+		for w != 0 {
+			if !f(i<<6 + bits.TrailingZeros64(w)) {
+				return
+			}
+			w &= w - 1
+		}
+	}
+}
+
+// check panics when i is not a valid index.
+func (b *Bitmap) check(i int) {
+	if i < 0 || b.n <= i {
+		panic("dac: bitmap index is out of bounds")
+	}
+}
+
+// same panics when other has a different length.
+func (b *Bitmap) same(other *Bitmap) {
+	if b.n != other.n {
+		panic("dac: bitmap lengths differ")
+	}
+}
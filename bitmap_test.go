@@ -0,0 +1,112 @@
+package dac
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomBitmap(r *rand.Rand, n int) (*Bitmap, []bool) {
+	b, want := NewBitmap(n), make([]bool, n)
+	for i := range want {
+		if r.Intn(3) == 0 {
+			b.Set(i)
+			want[i] = true
+		}
+	}
+	return b, want
+}
+
+func checkBitmap(t *testing.T, b *Bitmap, want []bool) {
+	t.Helper()
+
+	var count int
+	for i, v := range want {
+		if b.Contains(i) != v {
+			t.Fatalf("index %d - got: %v, want: %v\n", i, !v, v)
+		}
+		if v {
+			count++
+		}
+	}
+	if got := b.Count(); got != count {
+		t.Fatalf("Count - got: %d, want: %d\n", got, count)
+	}
+
+	prev := -1
+	b.Iterate(func(idx int) bool {
+		if idx <= prev || !want[idx] {
+			t.Fatalf("Iterate - unexpected index %d after %d\n", idx, prev)
+		}
+		prev = idx
+		count--
+		return true
+	})
+	if count != 0 {
+		t.Fatalf("Iterate - %d indexes missing\n", count)
+	}
+}
+
+func TestBitmap(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	for _, n := range []int{0, 1, 64, 1_000} {
+		a, wa := randomBitmap(r, n)
+		b, wb := randomBitmap(r, n)
+		checkBitmap(t, a, wa)
+
+		and, or, andNot := NewBitmap(n), NewBitmap(n), NewBitmap(n)
+		and.Or(a)
+		and.And(b)
+		or.Or(a)
+		or.Or(b)
+		andNot.Or(a)
+		andNot.AndNot(b)
+
+		wAnd, wOr, wAndNot := make([]bool, n), make([]bool, n), make([]bool, n)
+		for i := range wa {
+			wAnd[i], wOr[i], wAndNot[i] = wa[i] && wb[i], wa[i] || wb[i], wa[i] && !wb[i]
+		}
+		checkBitmap(t, and, wAnd)
+		checkBitmap(t, or, wOr)
+		checkBitmap(t, andNot, wAndNot)
+
+		if n != 0 {
+			a.Clear(n - 1)
+			wa[n-1] = false
+			checkBitmap(t, a, wa)
+		}
+	}
+}
+
+func TestBitmapIterateStop(t *testing.T) {
+	b := NewBitmap(200)
+	b.Set(3)
+	b.Set(70)
+	b.Set(199)
+
+	var got []int
+	b.Iterate(func(idx int) bool {
+		got = append(got, idx)
+		return idx < 70
+	})
+	if len(got) != 2 || got[0] != 3 || got[1] != 70 {
+		t.Errorf("Iterate - got: %v, want: [3 70]\n", got)
+	}
+}
+
+func TestBitmapPanics(t *testing.T) {
+	for _, f := range []func(){
+		func() { NewBitmap(10).Set(10) },
+		func() { NewBitmap(10).Contains(-1) },
+		func() { NewBitmap(10).And(NewBitmap(11)) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,177 @@
+package dac
+
+import "errors"
+
+// Builder constructs a closed dictionary in which every level is allocated at
+// exactly the right size. The values are either passed up front to Build, or
+// handed over in two passes. The first pass registers each value with
+// Observe, which only counts the values per byte length. The second pass
+// writes the same values with Write, after which Dict returns the result.
+// The values of the second pass may come in any order, as long as their byte
+// lengths match the ones observed in the first pass. The dictionary is
+// allocated by the first Write, so all values must be observed before then.
+type Builder struct {
+	hist [nStreams64]int // number of observed values per byte length
+	left [nStreams64]int // number of values left to write per byte length
+	pos  [nStreams64]int // write position per level
+	d    *Dict
+	err  error // set when a value is observed after the first Write
+}
+
+// Build constructs a closed dictionary from the given values. Build resets
+// the builder.
+func (b *Builder) Build(values []uint64) *Dict {
+	b.Reset()
+	b.ObserveList(values)
+
+	// the written values are the observed ones, so neither call can fail
+	b.WriteList(values)
+	d, _ := b.Dict()
+	return d
+}
+
+// Observe registers a value in the first pass. A value observed after the
+// first Write cannot be accommodated, so Write and Dict then return an error.
+func (b *Builder) Observe(v uint64) {
+	if b.canObserve() {
+		b.hist[maxByteIdx(v)]++
+	}
+}
+
+// ObserveList registers a slice of values in the first pass. See Observe.
+func (b *Builder) ObserveList(values []uint64) {
+	if !b.canObserve() {
+		return
+	}
+	for _, v := range values {
+		b.hist[maxByteIdx(v)]++
+	}
+}
+
+// Write writes a value in the second pass. An error is returned when more
+// values of the byte length of v are written than were observed, or when a
+// value was observed after the first Write.
+func (b *Builder) Write(v uint64) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.d == nil {
+		b.alloc()
+	}
+
+	n := maxByteIdx(v)
+	if b.left[n] == 0 {
+		return errors.New("dac: value does not match the observed values")
+	}
+	b.left[n]--
+
+	d := b.d
+	for l := 0; l < n; l++ {
+		k := b.pos[l]
+		d.chunks[l][k] = uint8(v)
+		d.bitArr[l][k>>6] |= 1 << (k & 63)
+		b.pos[l]++
+		v >>= 8
+	}
+	d.chunks[n][b.pos[n]] = uint8(v)
+	b.pos[n]++
+
+	return nil
+}
+
+// WriteList writes a slice of values in the second pass.
+func (b *Builder) WriteList(values []uint64) error {
+	for _, v := range values {
+		if err := b.Write(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dict closes and returns the dictionary built in the second pass. An error
+// is returned when fewer values were written than observed, or when a value
+// was observed after the first Write. Dict resets the builder.
+func (b *Builder) Dict() (*Dict, error) {
+	if err := b.err; err != nil {
+		b.Reset()
+		return nil, err
+	}
+	if b.d == nil {
+		b.alloc()
+	}
+
+	if b.left != [nStreams64]int{} {
+		return nil, errors.New("dac: fewer values written than observed")
+	}
+
+	d := b.d
+	d.Close()
+	b.Reset()
+
+	return d, nil
+}
+
+// Reset resets the builder, discarding the observed and written values.
+func (b *Builder) Reset() {
+	*b = Builder{}
+}
+
+// canObserve reports whether values can still be observed. Once values were
+// written, the dictionary cannot grow to hold more observed values, so the
+// misuse is recorded instead.
+func (b *Builder) canObserve() bool {
+	if b.d != nil {
+		b.err = errors.New("dac: value observed after the first write")
+		return false
+	}
+	return true
+}
+
+// alloc allocates a dictionary for the observed values.
+func (b *Builder) alloc() {
+	b.d = &Dict{}
+	b.left = b.hist
+
+	// a value of n bytes has an entry at levels 0 to n-1
+	var total int
+	for l := nStreams64 - 1; 0 <= l; l-- {
+		total += b.hist[l]
+		b.d.chunks[l] = make([]byte, total)
+		if l < nStreams64-1 {
+			words := (total + 63) >> 6
+			b.d.bitArr[l] = make([]uint64, words)
+			b.d.ranks[l] = make([]int, (words+7)>>3)
+		}
+	}
+}
+
+// Compact releases the capacity of the dictionary that is not in use. The
+// dictionary should be closed before calling Compact.
+func (d *Dict) Compact() {
+	for l := range d.chunks {
+		if len(d.chunks[l]) != cap(d.chunks[l]) {
+			chunks := make([]byte, len(d.chunks[l]))
+			copy(chunks, d.chunks[l])
+			d.chunks[l] = chunks
+		}
+	}
+
+	for l := range d.bitArr {
+		if len(d.bitArr[l]) != cap(d.bitArr[l]) {
+			arr := make([]uint64, len(d.bitArr[l]))
+			copy(arr, d.bitArr[l])
+			d.bitArr[l] = arr
+		}
+
+		n := (len(d.bitArr[l]) + 7) >> 3
+		if n < len(d.ranks[l]) {
+			d.ranks[l] = d.ranks[l][:n]
+		}
+		if len(d.ranks[l]) != cap(d.ranks[l]) {
+			ranks := make([]int, len(d.ranks[l]))
+			copy(ranks, d.ranks[l])
+			d.ranks[l] = ranks
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package dac
+
+import "testing"
+
+func TestBuilderBuild(t *testing.T) {
+	for _, n := range []int{0, 1, 512, 10_000} {
+		numbers := zipfNumbers(n, 15)
+
+		var b Builder
+		d := b.Build(numbers)
+		if !equalDict(d, From(numbers)) {
+			t.Errorf("n: %d - dictionaries differ\n", n)
+		}
+		checkDict(t, d, numbers)
+
+		for l := range d.chunks {
+			if cap(d.chunks[l]) != len(d.chunks[l]) {
+				t.Errorf("n: %d - chunks[%d] has spare capacity\n", n, l)
+			}
+		}
+		for l := range d.bitArr {
+			if cap(d.bitArr[l]) != len(d.bitArr[l]) || cap(d.ranks[l]) != len(d.ranks[l]) {
+				t.Errorf("n: %d - bitArr[%d] or ranks[%d] has spare capacity\n", n, l, l)
+			}
+		}
+	}
+}
+
+func TestBuilderTwoPass(t *testing.T) {
+	numbers := zipfNumbers(1_000, 15)
+
+	var b Builder
+	for _, v := range numbers {
+		b.Observe(v)
+	}
+	for _, v := range numbers {
+		if err := b.Write(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := b.Dict()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDict(t, d, numbers)
+}
+
+func TestBuilderErrors(t *testing.T) {
+	var b Builder
+	b.ObserveList([]uint64{1, 2, 300})
+
+	if err := b.WriteList([]uint64{1, 300}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Write(400); err == nil {
+		t.Error("expected error for unobserved byte length")
+	}
+	if _, err := b.Dict(); err == nil {
+		t.Error("expected error for missing values")
+	}
+}
+
+func TestBuilderObserveAfterWrite(t *testing.T) {
+	var b Builder
+	b.Observe(1)
+	b.Observe(300)
+	if err := b.Write(1); err != nil {
+		t.Fatal(err)
+	}
+
+	b.ObserveList([]uint64{2})
+	if err := b.Write(300); err == nil {
+		t.Error("expected error for Write after a late Observe")
+	}
+	if _, err := b.Dict(); err == nil {
+		t.Error("expected error for Dict after a late Observe")
+	}
+
+	// Dict resets the builder
+	b.Observe(2)
+	if err := b.Write(2); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := b.Dict(); err != nil || Len(d) != 1 {
+		t.Errorf("after Reset - got err: %v\n", err)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	numbers := zipfNumbers(1_000, 15)
+
+	d, err := New(10_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range numbers {
+		d.WriteU64(v)
+	}
+	d.Close()
+	d.Compact()
+
+	for l := range d.chunks {
+		if cap(d.chunks[l]) != len(d.chunks[l]) {
+			t.Errorf("chunks[%d] has spare capacity\n", l)
+		}
+	}
+	for l := range d.bitArr {
+		if cap(d.bitArr[l]) != len(d.bitArr[l]) || cap(d.ranks[l]) != len(d.ranks[l]) {
+			t.Errorf("bitArr[%d] or ranks[%d] has spare capacity\n", l, l)
+		}
+	}
+	checkDict(t, d, numbers)
+}
+
+func BenchmarkBuilderBuild(b *testing.B) {
+	const n = 1_000
+
+	numbers := zipfNumbers(n, 15)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var bld Builder
+		bld.Build(numbers)
+	}
+}
@@ -0,0 +1,149 @@
+package dac
+
+import "errors"
+
+// InsertU64ListAt inserts a slice of uint64 values at index k of the
+// dictionary. The affected levels are shifted only once, which is much
+// faster than calling InsertU64At for every value. Like inserting into a
+// slice, k may range from 0 up to and including Len.
+func (d *Dict) InsertU64ListAt(k int, values []uint64) error {
+	if k < 0 || len(d.chunks[0]) < k {
+		return errors.New("dac: index k is out of bounds")
+	}
+
+	d.splice(k, k, values)
+	return nil
+}
+
+// PrependU64List inserts a slice of uint64 values at the start of the
+// dictionary.
+func (d *Dict) PrependU64List(values []uint64) {
+	d.splice(0, 0, values)
+}
+
+// RemoveRange removes the entries in the index range [lo, hi) from the
+// dictionary.
+func (d *Dict) RemoveRange(lo, hi int) error {
+	if lo < 0 || hi < lo || len(d.chunks[0]) < hi {
+		return errors.New("dac: index range is out of bounds")
+	}
+
+	d.splice(lo, hi, nil)
+	return nil
+}
+
+// RemoveIndexes removes the entries at the given indexes from the dictionary.
+// The indexes must be sorted in strictly ascending order.
+func (d *Dict) RemoveIndexes(sortedIdx []int) error {
+	for i, k := range sortedIdx {
+		if k < 0 || len(d.chunks[0]) <= k {
+			return errors.New("dac: index k is out of bounds")
+		}
+		if i != 0 && k <= sortedIdx[i-1] {
+			return errors.New("dac: indexes are not sorted in strictly ascending order")
+		}
+	}
+
+	if len(sortedIdx) != 0 {
+		d.mods++
+		d.invalidateRMQ()
+	}
+
+	idx := sortedIdx
+	for l := uint(0); l < nStreams64 && len(idx) != 0; l++ {
+		// indexes of the next level, before level l gets modified
+		var next []int
+		if l < nStreams64-1 {
+			for _, k := range idx {
+				if d.bit(l, k) {
+					next = append(next, d.rank(l, k))
+				}
+			}
+		}
+
+		// shift the entries between the removed ones to the left
+		chunks := d.chunks[l]
+		w := idx[0]
+		for i, k := range idx {
+			end := len(chunks)
+			if i+1 < len(idx) {
+				end = idx[i+1]
+			}
+
+			copy(chunks[w:], chunks[k+1:end])
+			if l < nStreams64-1 {
+				copyBits(d.bitArr[l], w, d.bitArr[l], k+1, end-k-1)
+			}
+			w += end - k - 1
+		}
+		d.chunks[l] = chunks[:w]
+
+		if l < nStreams64-1 {
+			d.truncBits(l, w)
+			d.updateRanks(l, idx[0])
+		}
+		idx = next
+	}
+
+	return nil
+}
+
+// UpdateU64Range overwrites the entries starting at index lo with the given
+// uint64 values.
+func (d *Dict) UpdateU64Range(lo int, values []uint64) error {
+	if lo < 0 || len(d.chunks[0]) < lo+len(values) {
+		return errors.New("dac: index range is out of bounds")
+	}
+
+	d.splice(lo, lo+len(values), values)
+	return nil
+}
+
+// splice replaces the entries in the index range [lo, hi) by the given
+// values. The levels are rebuilt one after the other. Each level is shifted
+// at most once, after which its ranks are refreshed.
+func (d *Dict) splice(lo, hi int, values []uint64) {
+	if lo == hi && len(values) == 0 {
+		return
+	}
+	d.mods++
+	d.invalidateRMQ()
+
+	var ins Dict
+	ins.WriteU64List(values)
+
+	p, q := lo, hi
+	for l := uint(0); l < nStreams64; l++ {
+		m := len(ins.chunks[l])
+		if p == q && m == 0 {
+			return
+		}
+
+		// range of the next level, before level l gets modified
+		var pn, qn int
+		if l < nStreams64-1 {
+			pn, qn = d.rankAt(l, p), d.rankAt(l, q)
+		}
+
+		n := len(d.chunks[l])
+		nn := n - (q - p) + m
+		if n < nn {
+			d.chunks[l] = append(d.chunks[l], make([]byte, nn-n)...)
+		}
+		copy(d.chunks[l][p+m:], d.chunks[l][q:n])
+		copy(d.chunks[l][p:], ins.chunks[l])
+		d.chunks[l] = d.chunks[l][:nn]
+
+		if l < nStreams64-1 {
+			for words := (nn + 63) >> 6; len(d.bitArr[l]) < words; {
+				d.bitArr[l] = append(d.bitArr[l], 0)
+			}
+			copyBits(d.bitArr[l], p+m, d.bitArr[l], q, n-q)
+			copyBits(d.bitArr[l], p, ins.bitArr[l], 0, m)
+			d.truncBits(l, nn)
+			d.updateRanks(l, p)
+		}
+
+		p, q = pn, qn
+	}
+}
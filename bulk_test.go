@@ -0,0 +1,202 @@
+package dac
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// checkDict verifies that d holds exactly the values in want, both through
+// sequential and through direct reads.
+func checkDict(t *testing.T, d *Dict, want []uint64) {
+	t.Helper()
+
+	if err := d.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if Len(d) != len(want) {
+		t.Fatalf("Len - got: %d, want: %d\n", Len(d), len(want))
+	}
+
+	values := d.ReadU64List(nil)
+	for k := range want {
+		if values[k] != want[k] {
+			t.Fatalf("ReadU64List k: %d - got: %d, want: %d\n", k, values[k], want[k])
+		}
+		got, err := d.ReadU64(k)
+		if err != nil || got != want[k] {
+			t.Fatalf("ReadU64 k: %d - got: %d, want: %d, err: %v\n", k, got, want[k], err)
+		}
+	}
+}
+
+func zipfNumbers(n int, seed int64) []uint64 {
+	r := rand.New(rand.NewSource(seed))
+	zipf := rand.NewZipf(r, 1.15, 1, math.MaxUint64)
+
+	numbers := make([]uint64, n)
+	for i := range numbers {
+		numbers[i] = zipf.Uint64()
+	}
+	return numbers
+}
+
+func TestInsertU64ListAt(t *testing.T) {
+	const n = 1_500
+
+	numbers := zipfNumbers(n, 15)
+	inserts := zipfNumbers(700, 16)
+
+	for _, k := range []int{0, 1, 63, 64, 511, 512, 777, n - 1, n} {
+		d := From(numbers)
+		if err := d.InsertU64ListAt(k, inserts); err != nil {
+			t.Fatal(err)
+		}
+
+		want := append(append(append([]uint64{}, numbers[:k]...), inserts...), numbers[k:]...)
+		checkDict(t, d, want)
+	}
+
+	d := From(numbers)
+	if err := d.InsertU64ListAt(-1, inserts); err == nil {
+		t.Error("expected error for negative index")
+	}
+	if err := d.InsertU64ListAt(n+1, inserts); err == nil {
+		t.Error("expected error for index beyond length")
+	}
+}
+
+func TestInsertU64ListAtEmpty(t *testing.T) {
+	numbers := zipfNumbers(1_000, 15)
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.InsertU64ListAt(0, numbers[500:]); err != nil {
+		t.Fatal(err)
+	}
+	d.PrependU64List(numbers[:500])
+	checkDict(t, d, numbers)
+}
+
+func TestRemoveRange(t *testing.T) {
+	const n = 1_500
+
+	numbers := zipfNumbers(n, 15)
+
+	ranges := [][2]int{{0, 0}, {0, 1}, {0, n}, {3, 70}, {64, 128}, {500, 1_100}, {n - 1, n}, {700, n}}
+	for _, r := range ranges {
+		d := From(numbers)
+		if err := d.RemoveRange(r[0], r[1]); err != nil {
+			t.Fatal(err)
+		}
+
+		want := append(append([]uint64{}, numbers[:r[0]]...), numbers[r[1]:]...)
+		checkDict(t, d, want)
+	}
+
+	d := From(numbers)
+	if err := d.RemoveRange(5, 4); err == nil {
+		t.Error("expected error for inverted range")
+	}
+	if err := d.RemoveRange(0, n+1); err == nil {
+		t.Error("expected error for range beyond length")
+	}
+}
+
+func TestRemoveIndexes(t *testing.T) {
+	const n = 1_500
+
+	numbers := zipfNumbers(n, 15)
+
+	r := rand.New(rand.NewSource(15))
+	var idx []int
+	var want []uint64
+	for k, v := range numbers {
+		if r.Intn(3) == 0 {
+			idx = append(idx, k)
+		} else {
+			want = append(want, v)
+		}
+	}
+
+	d := From(numbers)
+	if err := d.RemoveIndexes(idx); err != nil {
+		t.Fatal(err)
+	}
+	checkDict(t, d, want)
+
+	all := make([]int, n)
+	for k := range all {
+		all[k] = k
+	}
+	d = From(numbers)
+	if err := d.RemoveIndexes(all); err != nil {
+		t.Fatal(err)
+	}
+	checkDict(t, d, nil)
+
+	d = From(numbers)
+	if err := d.RemoveIndexes([]int{4, 4}); err == nil {
+		t.Error("expected error for duplicate indexes")
+	}
+	if err := d.RemoveIndexes([]int{4, n}); err == nil {
+		t.Error("expected error for index beyond length")
+	}
+}
+
+func TestUpdateU64Range(t *testing.T) {
+	const n = 1_500
+
+	numbers := zipfNumbers(n, 15)
+	updates := zipfNumbers(600, 16)
+
+	for _, lo := range []int{0, 1, 64, 513, n - 600} {
+		d := From(numbers)
+		if err := d.UpdateU64Range(lo, updates); err != nil {
+			t.Fatal(err)
+		}
+
+		want := append([]uint64{}, numbers...)
+		copy(want[lo:], updates)
+		checkDict(t, d, want)
+	}
+
+	d := From(numbers)
+	if err := d.UpdateU64Range(n-599, updates); err == nil {
+		t.Error("expected error for range beyond length")
+	}
+}
+
+func BenchmarkInsertU64ListAt(b *testing.B) {
+	const n = 1_000
+
+	numbers := zipfNumbers(n, 15)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d := From([]uint64{0})
+		d.InsertU64ListAt(0, numbers)
+	}
+}
+
+func BenchmarkRemoveRange(b *testing.B) {
+	const n = 1_000
+
+	numbers := zipfNumbers(n, 15)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		d := From(numbers)
+		b.StartTimer()
+		d.RemoveRange(0, n)
+	}
+}
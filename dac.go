@@ -7,8 +7,10 @@ package dac
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/bits"
+	"sort"
 	"time"
 	"unsafe"
 )
@@ -21,6 +23,25 @@ const (
 	nStreams16 = 2
 )
 
+// ErrOverflow is returned when a value is read as a type that is too narrow
+// to hold it.
+var ErrOverflow = errors.New("dac: value overflows the read type")
+
+// OverflowError is returned by the list readers when a value is too wide for
+// the type read. It holds the index of the first such value.
+type OverflowError struct {
+	Index int // index of the first overflowing value
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("dac: value at index %d overflows the read type", e.Index)
+}
+
+// Unwrap returns ErrOverflow.
+func (e *OverflowError) Unwrap() error {
+	return ErrOverflow
+}
+
 // Dict is a dictionary type that stores values in a "Directly Addressable
 // Codes" structure. Data is compressed but still provides direct access
 // to any value. Moreover, data can be searched efficiently when stored in
@@ -29,10 +50,10 @@ type Dict struct {
 	chunks [nStreams64][]byte
 	bitArr [nStreams64 - 1][]uint64
 	ranks  [nStreams64 - 1][]int
+	rmq    *rmqIndex // optional range minimum and maximum query index
+	mods   uint64    // number of changes to the level layout, see Iterator
 }
 
-// TODO: Serdes van en naar file voorzien?!
-
 // New constructs a dictionary with an initial capacity of n values. Setting
 // the capacity is optional but recommended for performance reasons. The
 // capacity gets automatically updated when needed.
@@ -72,7 +93,8 @@ func Len(d *Dict) int {
 // Close builds support structures that improve the performance of direct reads.
 // You should not do any direct reads before calling Close, as the read will
 // crash. You can still write to the dictionary after a call to Close, but you
-// will have to close the dictionary again before doing any direct reads.
+// will have to close the dictionary again before doing any direct reads. When
+// the RMQ index is enabled, Close rebuilds it as well.
 func (d *Dict) Close() { // BuildIndex() noemen???
 	for i := 0; i < nStreams64-1; i++ {
 		arr := d.bitArr[i]
@@ -89,11 +111,17 @@ func (d *Dict) Close() { // BuildIndex() noemen???
 			prefix += bits.OnesCount64(arr[j])
 		}
 	}
+
+	if d.rmq != nil {
+		d.rmq = buildRMQ(d)
+	}
 }
 
 // Reset resets the dictionary without releasing its resources. It allows to
 // re-use an existing dictionary.
 func (d *Dict) Reset() {
+	d.mods++
+	d.invalidateRMQ()
 	for i := range d.chunks {
 		d.chunks[i] = d.chunks[i][:0]
 	}
@@ -166,6 +194,8 @@ func (d *Dict) RemoveAt(k int) error {
 		return errors.New("dac: index k is out of bounds")
 	}
 
+	d.mods++
+	d.invalidateRMQ()
 	d.chunks[0] = append(d.chunks[0][:k], d.chunks[0][k+1:]...)
 	l := uint(0)
 
@@ -183,12 +213,16 @@ func (d *Dict) RemoveAt(k int) error {
 	return nil
 }
 
-// InsertU64At inserts a uint64 value at index k of the dictionary.
+// InsertU64At inserts a uint64 value at index k of the dictionary. Like
+// inserting into a slice, k may range from 0 up to and including Len. The
+// value is appended when k equals Len.
 func (d *Dict) InsertU64At(k int, v uint64) error {
-	if k < 0 || len(d.chunks[0]) <= k {
+	if k < 0 || len(d.chunks[0]) < k {
 		return errors.New("dac: index k is out of bounds")
 	}
 
+	d.mods++
+	d.invalidateRMQ()
 	d.chunks[0] = append(d.chunks[0], 0)
 	copy(d.chunks[0][k+1:], d.chunks[0][k:])
 	d.chunks[0][k] = uint8(v)
@@ -211,12 +245,18 @@ func (d *Dict) InsertU64At(k int, v uint64) error {
 	return nil
 }
 
+// PrependU64 inserts a uint64 value at the start of the dictionary.
+func (d *Dict) PrependU64(v uint64) {
+	d.InsertU64At(0, v)
+}
+
 // UpdateU64At updates a uint64 value at index k of the dictionary.
 func (d *Dict) UpdateU64At(k int, v uint64) error {
 	if k < 0 || len(d.chunks[0]) <= k {
 		return errors.New("dac: index k is out of bounds")
 	}
 
+	d.invalidateRMQ()
 	d.chunks[0][k] = uint8(v)
 	v >>= 8
 	l := uint(0)
@@ -230,6 +270,7 @@ func (d *Dict) UpdateU64At(k int, v uint64) error {
 
 	// v==0, but still remnants of the original number
 	if l < nStreams64-1 && d.bit(l, k) {
+		d.mods++
 		kn := d.rank(l, k)
 		d.decrementIdx(l, k)
 		k, l = kn, l+1
@@ -250,6 +291,7 @@ func (d *Dict) UpdateU64At(k int, v uint64) error {
 
 	// v!=0 but no more remnants of original number
 	if v != 0 && l < nStreams64-1 {
+		d.mods++
 		d.incrementIdx(l, k)
 		k, l = d.rank(l, k), l+1
 		d.chunks[l] = append(d.chunks[l], 0)
@@ -328,7 +370,6 @@ func (d *Dict) removeIdx(l uint, k int) {
 	for i := k>>9 + 1; i < len(d.ranks[l]); i++ {
 		bb := int(d.bitArr[l][i<<3] & 1)
 		d.ranks[l][i] += bb - b
-		b = bb
 	}
 
 	// shorten ranks length if possible
@@ -375,7 +416,7 @@ func (d *Dict) decrementIdx(l uint, k int) {
 
 // WriteBoolList writes a slice of boolean values to the dictionary.
 func (d *Dict) WriteBoolList(values []bool) {
-	l := (len(d.chunks[0])+63)>>6 - len(d.bitArr[0])
+	l := (len(d.chunks[0])+len(values)+63)>>6 - len(d.bitArr[0])
 	d.bitArr[0] = append(d.bitArr[0], make([]uint64, l)...) // Dit kan sneller zonder de tweede make.
 
 	for _, v := range values {
@@ -473,22 +514,17 @@ func (d *Dict) WriteI32(v int32) int { // TODO: too slow!!!
 
 // WriteI64 writes an int64 value to the dictionary.
 func (d *Dict) WriteI64(v int64) int {
-	uv := uint64((v << 1) ^ (v >> 63))
-	return d.WriteU64(uv)
+	return d.WriteU64(zigzag(v))
 }
 
 // WriteFloat32 writes a float32 value to the dictionary.
 func (d *Dict) WriteFloat32(v float32) int {
-	x := math.Float32bits(v)
-	uv := uint64(bits.ReverseBytes32(x))
-	// return d.WriteU32(uv) // Is d.WriteU64(uv) faster?
-	return d.WriteU64(uv)
+	return d.WriteU64(reverse32(v))
 }
 
 // WriteFloat64 writes a float64 value to the dictionary.
 func (d *Dict) WriteFloat64(v float64) int {
-	uv := bits.ReverseBytes64(math.Float64bits(v))
-	return d.WriteU64(uv)
+	return d.WriteU64(reverse64(v))
 }
 
 // WriteDateTime writes a time.Time value with nanosecond
@@ -499,7 +535,7 @@ func (d *Dict) WriteDateTime(t time.Time) int {
 
 // WriteI8List writes a slice of int8 values to the dictionary.
 func (d *Dict) WriteI8List(values []int8) {
-	l := (len(d.chunks[0])+63)>>6 - len(d.bitArr[0])
+	l := (len(d.chunks[0])+len(values)+63)>>6 - len(d.bitArr[0])
 	d.bitArr[0] = append(d.bitArr[0], make([]uint64, l)...) // Dit kan sneller zonder de tweede make.
 
 	for _, v := range values {
@@ -642,16 +678,45 @@ func (d *Dict) ReadBool(i int) (bool, error) {
 	return d.chunks[0][i] != 0, nil
 }
 
-// ReadU8 reads an uint8 value at a given index in the dictionary.
+// ReadU8 reads an uint8 value at a given index in the dictionary. When the
+// stored value is wider than 8 bits, its low-order byte is returned together
+// with ErrOverflow.
 func (d *Dict) ReadU8(i int) (uint8, error) {
+	if i < 0 || Len(d) <= i {
+		return 0, errors.New("dac: index k is out of bounds")
+	}
+	if d.bit(0, i) {
+		return d.chunks[0][i], ErrOverflow
+	}
+	return d.chunks[0][i], nil
+}
+
+// ReadU8Unchecked reads an uint8 value at a given index in the dictionary.
+// Wider values are silently truncated.
+func (d *Dict) ReadU8Unchecked(i int) (uint8, error) {
 	if i < 0 || Len(d) <= i {
 		return 0, errors.New("dac: index k is out of bounds")
 	}
 	return d.chunks[0][i], nil
 }
 
-// ReadU16 reads an uint16 value at a given index in the dictionary.
+// ReadU16 reads an uint16 value at a given index in the dictionary. When the
+// stored value is wider than 16 bits, its low-order bytes are returned
+// together with ErrOverflow.
 func (d *Dict) ReadU16(k int) (v uint16, err error) {
+	if v, err = d.ReadU16Unchecked(k); err != nil || !d.bit(0, k) {
+		return
+	}
+
+	if k = d.rank(0, k); d.bit(1, k) {
+		return v, ErrOverflow
+	}
+	return
+}
+
+// ReadU16Unchecked reads an uint16 value at a given index in the dictionary.
+// Wider values are silently truncated.
+func (d *Dict) ReadU16Unchecked(k int) (v uint16, err error) {
 	if k < 0 || len(d.chunks[0]) <= k {
 		return 0, errors.New("dac: index k is out of bounds")
 	}
@@ -667,13 +732,9 @@ func (d *Dict) ReadU16(k int) (v uint16, err error) {
 	return
 }
 
-// // ReadU32 reads an uint32 value at a given index in the dictionary.
-// func (d *Dict) ReadU32(i int) (uint32, error) { // TODO: too slow!!!
-// 	v, err := d.ReadU64(i)
-// 	return uint32(v), err
-// }
-
-// ReadU32 reads an uint32 value at a given index in the dictionary.
+// ReadU32 reads an uint32 value at a given index in the dictionary. When the
+// stored value is wider than 32 bits, its low-order bytes are returned
+// together with ErrOverflow.
 func (d *Dict) ReadU32(k int) (v uint32, err error) {
 	if k < 0 || len(d.chunks[0]) <= k {
 		return 0, errors.New("dac: index k is out of bounds")
@@ -682,6 +743,29 @@ func (d *Dict) ReadU32(k int) (v uint32, err error) {
 	buf := (*[nStreams32]byte)(unsafe.Pointer(&v))
 	buf[0] = d.chunks[0][k]
 
+	var l uint
+	for l < nStreams32-1 && d.bit(l, k) {
+		k = d.rank(l, k)
+		l++
+		buf[l] = d.chunks[l][k]
+	}
+
+	if l == nStreams32-1 && d.bit(l, k) {
+		return v, ErrOverflow
+	}
+	return
+}
+
+// ReadU32Unchecked reads an uint32 value at a given index in the dictionary.
+// Wider values are silently truncated.
+func (d *Dict) ReadU32Unchecked(k int) (v uint32, err error) {
+	if k < 0 || len(d.chunks[0]) <= k {
+		return 0, errors.New("dac: index k is out of bounds")
+	}
+
+	buf := (*[nStreams32]byte)(unsafe.Pointer(&v))
+	buf[0] = d.chunks[0][k]
+
 	if d.bit(0, k) {
 		k = d.rank(0, k)
 		buf[1] = d.chunks[1][k]
@@ -749,8 +833,10 @@ func (d *Dict) ReadBoolList(values []bool) []bool {
 // ReadU8List returns all values in the dictionary when they are of uint8
 // type. One can avoid the allocation of the return slice in ReadU8List by
 // supplying a slice of a size sufficient to store all values. Supplying a
-// slice is optional.
-func (d *Dict) ReadU8List(values []uint8) []uint8 {
+// slice is optional. When a value is too wide for the type, its low-order
+// bytes are stored and an *OverflowError holding the index of the first
+// such value is returned.
+func (d *Dict) ReadU8List(values []uint8) ([]uint8, error) {
 	m := len(d.chunks[0])
 	if len(values) < m {
 		values = make([]uint8, m)
@@ -761,14 +847,16 @@ func (d *Dict) ReadU8List(values []uint8) []uint8 {
 	for i := range values {
 		values[i] = d.chunks[0][i]
 	}
-	return values
+	return values, overflowAt(firstBit(d.bitArr[0]))
 }
 
 // ReadU16List returns all values in the dictionary when they are of uint16
 // type. One can avoid the allocation of the return slice in ReadU16List by
 // supplying a slice of a size sufficient to store all values. Supplying a
-// slice is optional.
-func (d *Dict) ReadU16List(values []uint16) []uint16 {
+// slice is optional. When a value is too wide for the type, its low-order
+// bytes are stored and an *OverflowError holding the index of the first
+// such value is returned.
+func (d *Dict) ReadU16List(values []uint16) ([]uint16, error) {
 	m := len(d.chunks[0])
 	if len(values) < m {
 		values = make([]uint16, m)
@@ -776,23 +864,28 @@ func (d *Dict) ReadU16List(values []uint16) []uint16 {
 		values = values[:m]
 	}
 
-	rank := -1
+	rank, overflow := -1, -1
 	for i := range values {
 		buf := (*[nStreams16]byte)(unsafe.Pointer(&values[i]))
 		buf[0] = d.chunks[0][i]
 		if d.bit(0, i) {
 			rank++
 			buf[1] = d.chunks[1][rank]
+			if overflow < 0 && d.bit(1, rank) {
+				overflow = i
+			}
 		}
 	}
-	return values
+	return values, overflowAt(overflow)
 }
 
 // ReadU32List returns all values in the dictionary when they are of uint32
 // type. One can avoid the allocation of the return slice in ReadU32List by
 // supplying a slice of a size sufficient to store all values. Supplying a
-// slice is optional.
-func (d *Dict) ReadU32List(values []uint32) []uint32 {
+// slice is optional. When a value is too wide for the type, its low-order
+// bytes are stored and an *OverflowError holding the index of the first
+// such value is returned.
+func (d *Dict) ReadU32List(values []uint32) ([]uint32, error) {
 	m := len(d.chunks[0])
 	if len(values) < m {
 		values = make([]uint32, m)
@@ -801,6 +894,7 @@ func (d *Dict) ReadU32List(values []uint32) []uint32 {
 	}
 
 	ranks := [nStreams32 - 1]int{-1, -1, -1}
+	overflow := -1
 	for i := range values {
 		buf := (*[nStreams32]byte)(unsafe.Pointer(&values[i]))
 		buf[0] = d.chunks[0][i]
@@ -812,8 +906,11 @@ func (d *Dict) ReadU32List(values []uint32) []uint32 {
 			j++
 			buf[j] = d.chunks[j][k]
 		}
+		if overflow < 0 && j == nStreams32-1 && d.bit(j, k) {
+			overflow = i
+		}
 	}
-	return values
+	return values, overflowAt(overflow)
 }
 
 // ReadU64List returns all values in the dictionary when they are of uint64
@@ -844,21 +941,45 @@ func (d *Dict) ReadU64List(values []uint64) []uint64 {
 	return values
 }
 
-// ReadI8 reads an int8 value at a given index in the dictionary.
+// ReadI8 reads an int8 value at a given index in the dictionary. When the
+// stored value is wider than 8 bits, ErrOverflow is returned.
 func (d *Dict) ReadI8(i int) (int8, error) {
 	uv, err := d.ReadU8(i)
 	return int8((uv >> 1) ^ -(uv & 1)), err
 }
 
-// ReadI16 reads an int16 value at a given index in the dictionary.
+// ReadI8Unchecked reads an int8 value at a given index in the dictionary.
+// Wider values are silently truncated.
+func (d *Dict) ReadI8Unchecked(i int) (int8, error) {
+	uv, err := d.ReadU8Unchecked(i)
+	return int8((uv >> 1) ^ -(uv & 1)), err
+}
+
+// ReadI16 reads an int16 value at a given index in the dictionary. When the
+// stored value is wider than 16 bits, ErrOverflow is returned.
 func (d *Dict) ReadI16(i int) (int16, error) {
-	uv, err := d.ReadU64(i) // TODO: Is dit juist? Moeten we niet via uint16 gaan???
+	uv, err := d.ReadU16(i)
+	return int16((uv >> 1) ^ -(uv & 1)), err
+}
+
+// ReadI16Unchecked reads an int16 value at a given index in the dictionary.
+// Wider values are silently truncated.
+func (d *Dict) ReadI16Unchecked(i int) (int16, error) {
+	uv, err := d.ReadU16Unchecked(i)
 	return int16((uv >> 1) ^ -(uv & 1)), err
 }
 
-// ReadI32 reads an int32 value at a given index in the dictionary.
+// ReadI32 reads an int32 value at a given index in the dictionary. When the
+// stored value is wider than 32 bits, ErrOverflow is returned.
 func (d *Dict) ReadI32(i int) (int32, error) {
-	uv, err := d.ReadU64(i)
+	uv, err := d.ReadU32(i)
+	return int32((uv >> 1) ^ -(uv & 1)), err
+}
+
+// ReadI32Unchecked reads an int32 value at a given index in the dictionary.
+// Wider values are silently truncated.
+func (d *Dict) ReadI32Unchecked(i int) (int32, error) {
+	uv, err := d.ReadU32Unchecked(i)
 	return int32((uv >> 1) ^ -(uv & 1)), err
 }
 
@@ -919,9 +1040,11 @@ func (d *Dict) ReadDateTime(i int) (time.Time, error) {
 
 // ReadI8List returns all values in the dictionary when they are of int8
 // type. One can avoid the allocation of the return slice in ReadI8List by
-// supplying a slice of a size sufficient to store all values. Still,
-// this is optional.
-func (d *Dict) ReadI8List(values []int8) []int8 {
+// supplying a slice of a size sufficient to store all values. Still, this
+// is optional. When a value is too wide for the type, its low-order bytes
+// are stored and an *OverflowError holding the index of the first such
+// value is returned.
+func (d *Dict) ReadI8List(values []int8) ([]int8, error) {
 	m := len(d.chunks[0])
 	if len(values) < m {
 		values = make([]int8, m)
@@ -933,14 +1056,16 @@ func (d *Dict) ReadI8List(values []int8) []int8 {
 		uv := d.chunks[0][i]
 		values[i] = int8((uv >> 1) ^ -(uv & 1))
 	}
-	return values
+	return values, overflowAt(firstBit(d.bitArr[0]))
 }
 
 // ReadI16List returns all values in the dictionary when they are of int16
 // type. One can avoid the allocation of the return slice in ReadI16List by
 // supplying a slice of a size sufficient to store all values. Still, this
-// is optional.
-func (d *Dict) ReadI16List(values []int16) []int16 {
+// is optional. When a value is too wide for the type, its low-order bytes
+// are stored and an *OverflowError holding the index of the first such
+// value is returned.
+func (d *Dict) ReadI16List(values []int16) ([]int16, error) {
 	m := len(d.chunks[0])
 	if len(values) < m {
 		values = make([]int16, m)
@@ -949,6 +1074,7 @@ func (d *Dict) ReadI16List(values []int16) []int16 {
 	}
 
 	rank, chunks0, chunks1 := -1, d.chunks[0], d.chunks[1]
+	overflow := -1
 	for i := range values {
 		var uv uint16
 		buf := (*[nStreams16]byte)(unsafe.Pointer(&uv))
@@ -957,17 +1083,22 @@ func (d *Dict) ReadI16List(values []int16) []int16 {
 		if d.bit(0, i) {
 			rank++
 			buf[1] = chunks1[rank]
+			if overflow < 0 && d.bit(1, rank) {
+				overflow = i
+			}
 		}
 		values[i] = int16((uv >> 1) ^ -(uv & 1))
 	}
-	return values
+	return values, overflowAt(overflow)
 }
 
 // ReadI32List returns all values in the dictionary when they are of int32
 // type. One can avoid the allocation of the return slice in ReadI32List by
 // supplying a slice of a size sufficient to store all values. Still, this
-// is optional.
-func (d *Dict) ReadI32List(values []int32) []int32 {
+// is optional. When a value is too wide for the type, its low-order bytes
+// are stored and an *OverflowError holding the index of the first such
+// value is returned.
+func (d *Dict) ReadI32List(values []int32) ([]int32, error) {
 	m := len(d.chunks[0])
 	if len(values) < m {
 		values = make([]int32, m)
@@ -976,6 +1107,7 @@ func (d *Dict) ReadI32List(values []int32) []int32 {
 	}
 
 	ranks := [nStreams32 - 1]int{-1, -1, -1}
+	overflow := -1
 	for i := range values {
 		var uv uint32
 		buf := (*[nStreams32]byte)(unsafe.Pointer(&uv))
@@ -988,9 +1120,12 @@ func (d *Dict) ReadI32List(values []int32) []int32 {
 			j++
 			buf[j] = d.chunks[j][k]
 		}
+		if overflow < 0 && j == nStreams32-1 && d.bit(j, k) {
+			overflow = i
+		}
 		values[i] = int32((uv >> 1) ^ -(uv & 1))
 	}
-	return values
+	return values, overflowAt(overflow)
 }
 
 // ReadI64List returns all values in the dictionary when they are of int64
@@ -1119,35 +1254,17 @@ func (d *Dict) ReadDateTimeList(dateTimes []time.Time) []time.Time {
 // Scan returns the index of the first instance of the search value in the
 // dictionary. If the value is not found, -1 is returned. When the values
 // are sorted, Search is going to be faster than Scan.
-func (d *Dict) Scan(value uint64) (idx int) { // TODO: We zouden beter de high levels eerst scannen. Dan hebben we echter een Select() algoritme nodig!
-	buf := (*[nStreams64]byte)(unsafe.Pointer(&value))
-	n := maxByteIdx(value)
-
-	search := buf[0]
-	for i, v := range d.chunks[0] {
-		if v == search {
-			// search and v have length 1
-			if n == 0 && !d.bit(0, i) {
-				return i
-			}
-
-			// search and value are longer than 1 byte
-			k, l := i, uint(0)
-			for l < uint(n) && d.bit(l, k) {
-				k = d.rank(l, k)
-				l++
-				// not equal
-				if buf[l] != d.chunks[l][k] {
-					break
-				}
-				// equal and same length
-				if l == uint(n) && ((l < nStreams64-1 && !d.bit(l, k)) || (l == nStreams64-1)) {
-					return i
-				}
-			}
-		}
-	}
-	return -1
+//
+// Scan starts at the highest level that the search value reaches. Only the
+// entries of that level that end a value are candidates. They are mapped
+// back to level 0 with select, checking one byte per level on the way.
+func (d *Dict) Scan(value uint64) (idx int) {
+	idx = -1
+	d.scan(value, func(i int) bool {
+		idx = i
+		return false
+	})
+	return
 }
 
 // Search returns the indexes in the dictionary of the searched value.
@@ -1199,6 +1316,107 @@ func (d *Dict) bit(stream uint, pos int) bool {
 	return d.bitArr[stream][pos>>6]&(1<<(pos&63)) != 0
 }
 
+// rankAt is like rank, but also accepts k == len(chunks[l]), in which case
+// the total number of entries at level l+1 is returned.
+func (d *Dict) rankAt(l uint, k int) int {
+	if len(d.chunks[l]) <= k {
+		return len(d.chunks[l+1])
+	}
+	return d.rank(l, k)
+}
+
+// ranksAt returns the ranks for sequential decoding from the k-th entry
+// onwards. For every level l, ranks[l] is the index at level l+1 of the last
+// entry that belongs to a value before index k, or -1 if there is none.
+func (d *Dict) ranksAt(k int) (ranks [nStreams64 - 1]int) {
+	for l := uint(0); l < nStreams64-1; l++ {
+		k = d.rankAt(l, k)
+		ranks[l] = k - 1
+	}
+	return
+}
+
+// decode returns the k-th value of the dictionary. The ranks hold the state
+// of a sequential decoding and are advanced past the k-th value.
+func (d *Dict) decode(k int, ranks *[nStreams64 - 1]int) (v uint64) {
+	buf := (*[nStreams64]byte)(unsafe.Pointer(&v))
+	buf[0] = d.chunks[0][k]
+
+	var l uint
+	for l < nStreams64-1 && d.bit(l, k) {
+		ranks[l]++
+		k = ranks[l]
+		l++
+		buf[l] = d.chunks[l][k]
+	}
+
+	return
+}
+
+// selectBit returns the position of the j-th set bit, counting from 0, of
+// level l. It is the inverse of rank: d.rank(l, d.selectBit(l, j)) == j.
+// The dictionary must be closed and the bit must exist.
+func (d *Dict) selectBit(l uint, j int) int {
+	arr, ranks := d.bitArr[l], d.ranks[l][:(len(d.bitArr[l])+7)>>3]
+
+	// last block with a rank not exceeding j
+	b := sort.Search(len(ranks), func(i int) bool { return j < ranks[i] }) - 1
+	j -= ranks[b]
+
+	i := b << 3
+	for c := bits.OnesCount64(arr[i]); c <= j; c = bits.OnesCount64(arr[i]) {
+		j -= c
+		i++
+	}
+
+	w := arr[i]
+	for ; j > 0; j-- {
+		w &= w - 1
+	}
+	return i<<6 + bits.TrailingZeros64(w)
+}
+
+// updateRanks recomputes ranks[l] from the block holding the k-th entry of
+// level l onwards. The blocks before it must still be valid.
+func (d *Dict) updateRanks(l uint, k int) {
+	arr := d.bitArr[l]
+
+	b := k >> 9
+	if last := len(d.ranks[l]) - 1; b > last {
+		b = last
+	}
+
+	var prefix int
+	if b < 0 {
+		b = 0
+	} else {
+		prefix = d.ranks[l][b]
+	}
+
+	n := (len(arr) + 7) >> 3
+	for len(d.ranks[l]) < n {
+		d.ranks[l] = append(d.ranks[l], 0)
+	}
+	d.ranks[l] = d.ranks[l][:n]
+
+	for j := b << 3; j < len(arr); j++ {
+		if j&7 == 0 {
+			d.ranks[l][j>>3] = prefix
+		}
+		prefix += bits.OnesCount64(arr[j])
+	}
+}
+
+// truncBits shortens the bit array of level l to n entries. Bits beyond
+// position n in the last word are cleared.
+func (d *Dict) truncBits(l uint, n int) {
+	words := (n + 63) >> 6
+	d.bitArr[l] = d.bitArr[l][:words]
+	if n&63 != 0 {
+		d.bitArr[l][words-1] &= 1<<(n&63) - 1
+	}
+}
+
 // extend extends the size of the bit array of a given stream.
 func (d *Dict) extend(l uint) {
 	if len(d.chunks[l])&63 == 1 && l < nStreams64-1 {
@@ -1206,6 +1424,25 @@ func (d *Dict) extend(l uint) {
 	}
 }
 
+// firstBit returns the position of the first bit set in arr, or -1 when no
+// bit is set.
+func firstBit(arr []uint64) int {
+	for i, w := range arr {
+		if w != 0 {
+			return i<<6 + bits.TrailingZeros64(w)
+		}
+	}
+	return -1
+}
+
+// overflowAt returns an *OverflowError for index k, or nil when k is negative.
+func overflowAt(k int) error {
+	if k < 0 {
+		return nil
+	}
+	return &OverflowError{Index: k}
+}
+
 // searchLo returns the lowest index of value in arr.
 func searchLo(arr []uint8, value uint8) int {
 	lo, hi := 0, len(arr) // Test doen om lineair te scannen indien lengte kleiner dan threshold!
@@ -1246,3 +1483,48 @@ func maxByteIdx(value uint64) int { // TODO: Nog nodig?
 	}
 	return n
 }
+
+// copyBits copies n bits from src, starting at bit position sOff, to dst,
+// starting at bit position dOff. The bits of dst outside the target range
+// are left untouched. Like the builtin copy, copyBits handles overlapping
+// ranges within the same bit array.
+func copyBits(dst []uint64, dOff int, src []uint64, sOff, n int) {
+	if dOff <= sOff {
+		for n > 0 {
+			m := 64 - dOff&63
+			if n < m {
+				m = n
+			}
+			writeBits(dst, dOff, m, readBits(src, sOff, m))
+			dOff, sOff, n = dOff+m, sOff+m, n-m
+		}
+		return
+	}
+
+	for n > 0 {
+		m := (dOff+n-1)&63 + 1
+		if n < m {
+			m = n
+		}
+		n -= m
+		writeBits(dst, dOff+n, m, readBits(src, sOff+n, m))
+	}
+}
+
+// readBits returns m <= 64 bits of arr, starting at bit position pos.
+func readBits(arr []uint64, pos, m int) uint64 {
+	i, s := pos>>6, uint(pos&63)
+	w := arr[i] >> s
+	if int(s)+m > 64 {
+		w |= arr[i+1] << (64 - s)
+	}
+	return w & (uint64(1)<<uint(m) - 1)
+}
+
+// writeBits writes the m low bits of w to arr, starting at bit position
+// pos. The bits must fit into a single word of arr.
+func writeBits(arr []uint64, pos, m int, w uint64) {
+	s := uint(pos & 63)
+	mask := (uint64(1)<<uint(m) - 1) << s
+	arr[pos>>6] = arr[pos>>6]&^mask | w<<s
+}
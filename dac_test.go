@@ -2,6 +2,7 @@ package dac
 
 import (
 	"encoding/binary"
+	"errors"
 	"math"
 	"math/rand"
 	"sort"
@@ -263,6 +264,64 @@ func TestInsert(t *testing.T) {
 	}
 }
 
+func TestInsertU64AtEnd(t *testing.T) {
+	const n = 1_000
+
+	r := rand.New(rand.NewSource(15))
+	zipf := rand.NewZipf(r, 1.15, 1, math.MaxUint64)
+
+	numbers := make([]uint64, n)
+	for i := range numbers {
+		numbers[i] = zipf.Uint64()
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, num := range numbers {
+		if err := d.InsertU64At(k, num); err != nil {
+			t.Fatalf("k: %d - err: %s\n", k, err)
+		}
+	}
+
+	for k, want := range numbers {
+		got, err := d.ReadU64(k)
+		if err != nil || got != want {
+			t.Errorf("k: %d - got: %d, want: %d, err: %v\n", k, got, want, err)
+		}
+	}
+
+	if err := d.InsertU64At(n+1, 0); err == nil {
+		t.Error("expected error for index beyond length")
+	}
+}
+
+func TestPrependU64(t *testing.T) {
+	const n = 1_000
+
+	r := rand.New(rand.NewSource(15))
+	zipf := rand.NewZipf(r, 1.15, 1, math.MaxUint64)
+
+	numbers := make([]uint64, n)
+	for i := range numbers {
+		numbers[i] = zipf.Uint64()
+	}
+
+	d := Dict{}
+	for i := n - 1; 0 <= i; i-- {
+		d.PrependU64(numbers[i])
+	}
+
+	values := d.ReadU64List(nil)
+	for k, want := range numbers {
+		if got := values[k]; got != want {
+			t.Errorf("k: %d - got: %d, want: %d\n", k, got, want)
+		}
+	}
+}
+
 func TestRemovea(t *testing.T) {
 	const n = 1_025
 
@@ -1444,6 +1503,84 @@ func TestReadDateTimeList(t *testing.T) {
 	}
 }
 
+func TestReadOverflow(t *testing.T) {
+	numbers := []uint64{1, 2, 0x1ff, 0x1ffff, 3, 0x1ffffffff, 0x1ffff}
+
+	d := From(numbers)
+
+	tests := []struct {
+		width int // width in bits of the read
+		first int // index of the first overflowing value
+		read  func(int) error
+	}{
+		{8, 2, func(k int) error { _, err := d.ReadU8(k); return err }},
+		{16, 3, func(k int) error { _, err := d.ReadU16(k); return err }},
+		{32, 5, func(k int) error { _, err := d.ReadU32(k); return err }},
+		{8, 2, func(k int) error { _, err := d.ReadI8(k); return err }},
+		{16, 3, func(k int) error { _, err := d.ReadI16(k); return err }},
+		{32, 5, func(k int) error { _, err := d.ReadI32(k); return err }},
+	}
+
+	for _, tt := range tests {
+		for k, v := range numbers {
+			err := tt.read(k)
+			if overflow := v>>tt.width != 0; overflow != (err == ErrOverflow) {
+				t.Errorf("width: %d, k: %d - got: %v, overflow: %v\n", tt.width, k, err, overflow)
+			}
+		}
+	}
+
+	if v, err := d.ReadU16(5); err != ErrOverflow || v != 0xffff {
+		t.Errorf("ReadU16(5) - got: (%#x, %v), want: (0xffff, %v)\n", v, err, ErrOverflow)
+	}
+	if v, err := d.ReadU8Unchecked(2); err != nil || v != 0xff {
+		t.Errorf("ReadU8Unchecked(2) - got: (%#x, %v)\n", v, err)
+	}
+	if v, err := d.ReadU16Unchecked(5); err != nil || v != 0xffff {
+		t.Errorf("ReadU16Unchecked(5) - got: (%#x, %v)\n", v, err)
+	}
+	if v, err := d.ReadU32Unchecked(5); err != nil || v != 0xffffffff {
+		t.Errorf("ReadU32Unchecked(5) - got: (%#x, %v)\n", v, err)
+	}
+	if v, err := d.ReadI8Unchecked(0); err != nil || v != -1 {
+		t.Errorf("ReadI8Unchecked(0) - got: (%d, %v)\n", v, err)
+	}
+	if v, err := d.ReadI16Unchecked(1); err != nil || v != 1 {
+		t.Errorf("ReadI16Unchecked(1) - got: (%d, %v)\n", v, err)
+	}
+	if v, err := d.ReadI32Unchecked(4); err != nil || v != -2 {
+		t.Errorf("ReadI32Unchecked(4) - got: (%d, %v)\n", v, err)
+	}
+
+	lists := []struct {
+		first int
+		read  func() error
+	}{
+		{2, func() error { _, err := d.ReadU8List(nil); return err }},
+		{3, func() error { _, err := d.ReadU16List(nil); return err }},
+		{5, func() error { _, err := d.ReadU32List(nil); return err }},
+		{2, func() error { _, err := d.ReadI8List(nil); return err }},
+		{3, func() error { _, err := d.ReadI16List(nil); return err }},
+		{5, func() error { _, err := d.ReadI32List(nil); return err }},
+	}
+
+	for _, tt := range lists {
+		err := tt.read()
+		var oe *OverflowError
+		if !errors.As(err, &oe) || oe.Index != tt.first || !errors.Is(err, ErrOverflow) {
+			t.Errorf("got: %v, want index: %d\n", err, tt.first)
+		}
+	}
+
+	small := From([]uint64{1, 2, 3})
+	if _, err := small.ReadU8List(nil); err != nil {
+		t.Error(err)
+	}
+	if _, err := small.ReadI32List(nil); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestScan(t *testing.T) {
 	const n = 1_000
 
@@ -0,0 +1,374 @@
+package dac
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+// pageSize is the number of bytes read at once from a DiskDict.
+const pageSize = 4096
+
+// DiskDict is a read-only dictionary that gives direct access to a serialized
+// dictionary through an io.ReaderAt, without loading it in memory. Only the
+// pages holding the requested entries are read. They are kept in a bounded
+// LRU cache. A DiskDict is safe for concurrent use.
+type DiskDict struct {
+	r     io.ReaderAt
+	lt    layout
+	cache pageCache
+}
+
+// OpenDiskDict opens the serialized dictionary that r reads from. At most
+// cachePages pages of 4 KiB are cached. When cachePages is smaller than 1, a
+// single page is cached. The number of entries of every level in the header
+// is checked against the presence bits of the level before, so that a
+// corrupt header is reported here rather than by a later read.
+func OpenDiskDict(r io.ReaderAt, cachePages int) (*DiskDict, error) {
+	var buf [headerSize]byte
+	if _, err := r.ReadAt(buf[:], 0); err != nil {
+		return nil, err
+	}
+
+	_, lens, err := parseHeader(buf[:])
+	if err != nil {
+		return nil, err
+	}
+
+	lt := newLayout(lens)
+	if _, err := r.ReadAt(buf[:1], lt.size-1); err != nil {
+		return nil, errors.New("dac: serialized dictionary is truncated")
+	}
+
+	if cachePages < 1 {
+		cachePages = 1
+	}
+
+	d := DiskDict{r: r, lt: lt}
+	d.cache.capacity = cachePages
+	d.cache.pages = make(map[int64]*list.Element, cachePages)
+	d.cache.lru.Init()
+
+	if err := d.checkLevels(); err != nil {
+		return nil, err
+	}
+	d.cache.hits, d.cache.misses = 0, 0
+
+	return &d, nil
+}
+
+// Len returns the number of entries in the dictionary.
+func (d *DiskDict) Len() int {
+	return d.lt.lens[0]
+}
+
+// CacheStats returns the number of page cache hits and misses so far.
+func (d *DiskDict) CacheStats() (hits, misses uint64) {
+	d.cache.mu.Lock()
+	defer d.cache.mu.Unlock()
+
+	return d.cache.hits, d.cache.misses
+}
+
+// ReadU64 reads an uint64 value at a given index in the dictionary.
+func (d *DiskDict) ReadU64(k int) (v uint64, err error) {
+	if k < 0 || d.lt.lens[0] <= k {
+		return 0, errors.New("dac: index k is out of bounds")
+	}
+
+	buf := (*[nStreams64]byte)(unsafe.Pointer(&v))
+	if buf[0], err = d.chunk(0, k); err != nil {
+		return 0, err
+	}
+
+	for l := uint(0); l < nStreams64-1; l++ {
+		ok, err := d.bit(l, k)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+
+		if k, err = d.rank(l, k); err != nil {
+			return 0, err
+		}
+		if buf[l+1], err = d.chunk(l+1, k); err != nil {
+			return 0, err
+		}
+	}
+
+	return v, nil
+}
+
+// ReadFloat64 reads a float64 value at a given index in the dictionary.
+func (d *DiskDict) ReadFloat64(k int) (float64, error) {
+	uv, err := d.ReadU64(k)
+	return math.Float64frombits(bits.ReverseBytes64(uv)), err
+}
+
+// Search returns the index of the first instance of the searched value and
+// the number of instances. If value is not found, -1 is returned. Search
+// should only be used when the dictionary is sorted. See Dict.Search.
+func (d *DiskDict) Search(value uint64) (idx, l int, err error) {
+	n := maxByteIdx(value)
+	lens := d.lt.lens
+
+	// skip values of smaller size
+	l = lens[n]
+	if n < nStreams64-1 {
+		l -= lens[n+1]
+	}
+
+	// cmp reports whether the byte of level n at index i satisfies f
+	cmp := func(i int, f func(c byte) bool) bool {
+		c, e := d.chunk(uint(n), i)
+		if e != nil {
+			if err == nil {
+				err = e
+			}
+			return true
+		}
+		return f(c)
+	}
+
+	// search from upper stream downwards
+	for {
+		b := uint8(value >> (8 * n))
+		lo := sort.Search(l, func(i int) bool {
+			return cmp(idx+i, func(c byte) bool { return b <= c })
+		})
+		if err != nil {
+			return -1, 0, err
+		}
+		if lo == l || !cmp(idx+lo, func(c byte) bool { return c == b }) {
+			return -1, 0, err
+		}
+		idx += lo
+
+		l = sort.Search(l-lo, func(i int) bool {
+			return cmp(idx+i, func(c byte) bool { return b < c })
+		})
+		if err != nil {
+			return -1, 0, err
+		}
+
+		if n--; n < 0 {
+			return
+		}
+		idx += lens[n] - lens[n+1]
+	}
+}
+
+// Iter creates an iterator for the dictionary.
+func (d *DiskDict) Iter() DiskIterator {
+	return DiskIterator{
+		d:     d,
+		ranks: [nStreams64 - 1]int{-1, -1, -1, -1, -1, -1, -1},
+	}
+}
+
+// DiskIterator enables sequential iteration over a DiskDict.
+type DiskIterator struct {
+	d     *DiskDict           // pointer to dictionary
+	ranks [nStreams64 - 1]int // rank (starting to count from 0)
+	k     int                 // current index
+	err   error               // first read error
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, or a read error occurred, the ok return value will be false.
+func (it *DiskIterator) Next() (k int, v uint64, ok bool) {
+	i, k := it.k, it.k
+	if it.err != nil || it.d.lt.lens[0] <= i {
+		return
+	}
+
+	buf := (*[nStreams64]byte)(unsafe.Pointer(&v))
+	if buf[0], it.err = it.d.chunk(0, i); it.err != nil {
+		return
+	}
+
+	for j := uint(0); j < nStreams64-1; j++ {
+		var set bool
+		if set, it.err = it.d.bit(j, i); it.err != nil {
+			return
+		}
+		if !set {
+			break
+		}
+
+		it.ranks[j]++
+		i = it.ranks[j]
+		if buf[j+1], it.err = it.d.chunk(j+1, i); it.err != nil {
+			return
+		}
+	}
+
+	it.k++
+	return k, v, true
+}
+
+// Err returns the first read error encountered by the iterator.
+func (it *DiskIterator) Err() error {
+	return it.err
+}
+
+// checkLevels verifies that the presence bits of every level agree with the
+// number of entries of the next level. Only the last block of a level is
+// read: the rank of its last entry, plus its own bit, must equal the number
+// of entries of the next level. The first rank must be zero and no bits may
+// be set beyond the last entry.
+func (d *DiskDict) checkLevels() error {
+	lens := d.lt.lens
+	for l := uint(0); l < nStreams64-1 && lens[l] != 0; l++ {
+		n := lens[l]
+
+		first, err := d.word(d.lt.ranks[l])
+		if err != nil {
+			return err
+		}
+		if first != 0 {
+			return fmt.Errorf("%w: level %d has an invalid rank for block 0", ErrCorrupt, l)
+		}
+
+		last, err := d.word(d.lt.bitArr[l] + 8*int64((n-1)>>6))
+		if err != nil {
+			return err
+		}
+		if n&63 != 0 && last>>(n&63) != 0 {
+			return fmt.Errorf("%w: level %d has bits set beyond its %d entries", ErrCorrupt, l, n)
+		}
+
+		r, err := d.rank(l, n-1)
+		if err != nil {
+			return err
+		}
+		if last&(1<<((n-1)&63)) != 0 {
+			r++
+		}
+		if r != lens[l+1] {
+			return fmt.Errorf("%w: level %d has %d bits set for %d entries at level %d", ErrCorrupt, l, r, lens[l+1], l+1)
+		}
+	}
+
+	return nil
+}
+
+// chunk returns the byte of level l at index k.
+func (d *DiskDict) chunk(l uint, k int) (byte, error) {
+	if k < 0 || d.lt.lens[l] <= k {
+		return 0, ErrCorrupt
+	}
+
+	off := d.lt.chunks[l] + int64(k)
+	page, err := d.cache.get(d.r, off&^(pageSize-1), d.lt.size)
+	if err != nil {
+		return 0, err
+	}
+	return page[off&(pageSize-1)], nil
+}
+
+// word returns the 64-bit word at the given offset, which is a multiple of 8.
+func (d *DiskDict) word(off int64) (uint64, error) {
+	page, err := d.cache.get(d.r, off&^(pageSize-1), d.lt.size)
+	if err != nil {
+		return 0, err
+	}
+
+	var w uint64
+	for i := 7; 0 <= i; i-- {
+		w = w<<8 | uint64(page[off&(pageSize-1)+int64(i)])
+	}
+	return w, nil
+}
+
+// bit returns whether the presence bit of level l at index k is set.
+func (d *DiskDict) bit(l uint, k int) (bool, error) {
+	w, err := d.word(d.lt.bitArr[l] + 8*int64(k>>6))
+	return w&(1<<(k&63)) != 0, err
+}
+
+// rank returns the rank of the (l+1)-th byte of the k-th number.
+func (d *DiskDict) rank(l uint, k int) (int, error) {
+	blockID := k >> 9
+	r, err := d.word(d.lt.ranks[l] + 8*int64(blockID))
+	if err != nil {
+		return 0, err
+	}
+	rank := int(r)
+
+	for i := blockID << 3; i <= k>>6; i++ {
+		w, err := d.word(d.lt.bitArr[l] + 8*int64(i))
+		if err != nil {
+			return 0, err
+		}
+		if i == k>>6 {
+			w &= 1<<(k&63) - 1
+		}
+		rank += bits.OnesCount64(w)
+	}
+
+	return rank, nil
+}
+
+// pageCache is a bounded LRU cache of pages.
+type pageCache struct {
+	mu       sync.Mutex
+	capacity int
+	pages    map[int64]*list.Element
+	lru      list.List // most recently used page at the front
+	hits     uint64
+	misses   uint64
+}
+
+// page is a cached page, starting at offset off.
+type page struct {
+	off  int64
+	data []byte
+}
+
+// get returns the page at offset off, reading it from r when not cached.
+// Pages are truncated at the given size. An offset outside [0, size), which
+// only a corrupt rank can produce, gives ErrCorrupt. The returned page must
+// not be modified.
+func (c *pageCache) get(r io.ReaderAt, off, size int64) ([]byte, error) {
+	if off < 0 || size <= off {
+		return nil, ErrCorrupt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.pages[off]; ok {
+		c.hits++
+		c.lru.MoveToFront(e)
+		return e.Value.(*page).data, nil
+	}
+	c.misses++
+
+	n := int64(pageSize)
+	if size-off < n {
+		n = size - off
+	}
+
+	// Evicted pages are not recycled, as callers may still be reading them.
+	p := &page{off: off, data: make([]byte, n)}
+	if m, err := r.ReadAt(p.data, off); int64(m) < n {
+		return nil, err
+	}
+
+	if c.lru.Len() == c.capacity {
+		e := c.lru.Back()
+		delete(c.pages, c.lru.Remove(e).(*page).off)
+	}
+	c.pages[off] = c.lru.PushFront(p)
+
+	return p.data, nil
+}
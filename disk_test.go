@@ -0,0 +1,231 @@
+package dac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+	"testing"
+)
+
+func serialize(t testing.TB, d *Dict) []byte {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDiskDictReadU64(t *testing.T) {
+	const n = 20_000
+
+	numbers := zipfNumbers(n, 15)
+	data := serialize(t, From(numbers))
+
+	for _, pages := range []int{0, 2, 1_000} {
+		d, err := OpenDiskDict(bytes.NewReader(data), pages)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Len() != n {
+			t.Fatalf("Len - got: %d, want: %d\n", d.Len(), n)
+		}
+
+		for k, want := range numbers {
+			got, err := d.ReadU64(k)
+			if err != nil || got != want {
+				t.Fatalf("pages: %d, k: %d - got: %d, want: %d, err: %v\n", pages, k, got, want, err)
+			}
+		}
+
+		if _, err := d.ReadU64(n); err == nil {
+			t.Error("expected error for index beyond length")
+		}
+
+		hits, misses := d.CacheStats()
+		if hits == 0 || misses == 0 {
+			t.Errorf("pages: %d - hits: %d, misses: %d\n", pages, hits, misses)
+		}
+	}
+}
+
+func TestDiskDictReadFloat64(t *testing.T) {
+	numbers := []float64{0, 1.5, -2.25, math.Pi, math.Inf(1), 1e300}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.WriteFloat64List(numbers)
+
+	dd, err := OpenDiskDict(bytes.NewReader(serialize(t, d)), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, want := range numbers {
+		got, err := dd.ReadFloat64(k)
+		if err != nil || got != want {
+			t.Errorf("k: %d - got: %v, want: %v, err: %v\n", k, got, want, err)
+		}
+	}
+}
+
+func TestDiskDictIter(t *testing.T) {
+	const n = 20_000
+
+	numbers := zipfNumbers(n, 15)
+	d, err := OpenDiskDict(bytes.NewReader(serialize(t, From(numbers))), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	it := d.Iter()
+	var count int
+	for {
+		k, got, ok := it.Next()
+		if !ok {
+			break
+		}
+		if got != numbers[k] {
+			t.Fatalf("k: %d - got: %d, want: %d\n", k, got, numbers[k])
+		}
+		count++
+	}
+	if it.Err() != nil || count != n {
+		t.Errorf("count: %d, err: %v\n", count, it.Err())
+	}
+}
+
+func TestDiskDictSearch(t *testing.T) {
+	const n = 5_000
+
+	numbers := zipfNumbers(n, 15)
+	sort.Slice(numbers, func(i, j int) bool {
+		return numbers[i] < numbers[j]
+	})
+
+	md := From(numbers)
+	d, err := OpenDiskDict(bytes.NewReader(serialize(t, md)), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range append(numbers, 0, 12_345, math.MaxUint64) {
+		wantIdx, wantLen := md.Search(v)
+		gotIdx, gotLen, err := d.Search(v)
+		if err != nil || gotIdx != wantIdx || gotLen != wantLen {
+			t.Errorf("Search %d - got: (%d, %d), want: (%d, %d), err: %v\n", v, gotIdx, gotLen, wantIdx, wantLen, err)
+		}
+	}
+}
+
+func TestOpenDiskDictErrors(t *testing.T) {
+	data := serialize(t, From(zipfNumbers(1_000, 15)))
+
+	if _, err := OpenDiskDict(bytes.NewReader(data[:len(data)-1]), 4); err == nil {
+		t.Error("expected error for truncated input")
+	}
+	if _, err := OpenDiskDict(bytes.NewReader(data[:10]), 4); err == nil {
+		t.Error("expected error for truncated header")
+	}
+}
+
+func TestOpenDiskDictCorrupt(t *testing.T) {
+	d := From(zipfNumbers(2_000, 15))
+	lens := lensOf(d)
+	data := serialize(t, d)
+
+	for l := 1; l < nStreams64 && lens[l] != 0; l++ {
+		for _, n := range []int{lens[l] - 1, lens[l] + 1} {
+			if lens[l-1] < n || (l+1 < nStreams64 && n < lens[l+1]) {
+				continue
+			}
+			bad := append([]byte{}, data...)
+			binary.LittleEndian.PutUint64(bad[8+8*l:], uint64(n))
+
+			// a larger count is reported as a truncated input instead
+			_, err := OpenDiskDict(bytes.NewReader(bad), 4)
+			if err == nil || (n < lens[l] && !errors.Is(err, ErrCorrupt)) {
+				t.Errorf("level %d with %d entries - got: %v, want: %v\n", l, n, err, ErrCorrupt)
+			}
+		}
+	}
+
+	// a bit set beyond the last entry of level 0
+	bad := append([]byte{}, data...)
+	lt := newLayout(lens)
+	off := lt.bitArr[0] + 8*int64((lens[0]-1)>>6)
+	bad[off+7] |= 0x80
+	if _, err := OpenDiskDict(bytes.NewReader(bad), 4); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("bit beyond the last entry - got: %v, want: %v\n", err, ErrCorrupt)
+	}
+}
+
+func TestDiskDictCorruptRank(t *testing.T) {
+	const n = 100_000
+
+	numbers := zipfNumbers(n, 15)
+	d := From(numbers)
+	data := serialize(t, d)
+	lt := newLayout(lensOf(d))
+
+	// a rank word in the middle of level 0, made negative or too large
+	const block = 50
+	for _, mask := range []byte{0x80, 0x40} {
+		bad := append([]byte{}, data...)
+		bad[lt.ranks[0]+8*block+7] ^= mask
+
+		dd, err := OpenDiskDict(bytes.NewReader(bad), 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		errs := 0
+		for k := block * 512; k < (block+1)*512; k++ {
+			v, err := dd.ReadU64(k)
+			switch {
+			case err != nil:
+				if !errors.Is(err, ErrCorrupt) {
+					t.Errorf("mask %#x - ReadU64(%d): %v\n", mask, k, err)
+				}
+				errs++
+			case v != numbers[k]:
+				t.Errorf("mask %#x - ReadU64(%d) - got: %d, want: %d\n", mask, k, v, numbers[k])
+			}
+		}
+		if errs == 0 {
+			t.Errorf("mask %#x - expected errors for the corrupt block\n", mask)
+		}
+
+		// the iterator and Search do not use the ranks, but must not panic
+		it := dd.Iter()
+		for k, v, ok := it.Next(); ok; k, v, ok = it.Next() {
+			if v != numbers[k] {
+				t.Errorf("mask %#x - Next(%d) - got: %d, want: %d\n", mask, k, v, numbers[k])
+				break
+			}
+		}
+		if _, _, err := dd.Search(numbers[block*512]); err != nil {
+			t.Errorf("mask %#x - Search: %v\n", mask, err)
+		}
+	}
+}
+
+func BenchmarkDiskDictReadU64(b *testing.B) {
+	const n = 100_000
+
+	data := serialize(b, From(zipfNumbers(n, 15)))
+	d, err := OpenDiskDict(bytes.NewReader(data), 64)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.ReadU64(i % n)
+	}
+}
@@ -0,0 +1,223 @@
+package dac
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/bits"
+	"os"
+)
+
+// Encoder writes a dictionary in serialized form to an io.Writer, without
+// holding the dictionary in memory. The bytes, presence bits and ranks of
+// every level are spilled to temporary files while the values are written.
+// On Close, the header and the spilled levels are copied to the io.Writer.
+// The memory use of an Encoder is bounded by its write buffers.
+type Encoder struct {
+	w      io.Writer
+	dir    string
+	lens   [nStreams64]int        // number of entries per level
+	chunks [nStreams64]*spill     // bytes per level
+	bitArr [nStreams64 - 1]*spill // completed presence bit words per level
+	ranks  [nStreams64 - 1]*spill // ranks per level
+	words  [nStreams64 - 1]uint64 // presence bit word in progress per level
+	prefix [nStreams64 - 1]int    // number of bits set in the completed words
+	buf    [8]byte                // scratch space
+	err    error                  // first error encountered
+	closed bool
+}
+
+// spill is a temporary file with a write buffer.
+type spill struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+// NewEncoder creates an encoder that writes to w. The temporary files are
+// created in directory dir. If dir is the empty string, the default
+// directory for temporary files is used.
+func NewEncoder(w io.Writer, dir string) *Encoder {
+	return &Encoder{w: w, dir: dir}
+}
+
+// WriteU64 writes a uint64 value to the encoder.
+func (e *Encoder) WriteU64(v uint64) error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.closed {
+		return errors.New("dac: encoder is closed")
+	}
+
+	l := 0
+	e.writeByte(l, uint8(v))
+
+	for v >>= 8; v != 0 && l < nStreams64-1; v >>= 8 {
+		e.words[l] |= 1 << (e.lens[l] & 63)
+		e.advance(l)
+		l++
+		e.writeByte(l, uint8(v))
+	}
+	if l < nStreams64-1 {
+		e.advance(l)
+	} else {
+		e.lens[l]++
+	}
+
+	return e.err
+}
+
+// WriteU64List writes a slice of uint64 values to the encoder.
+func (e *Encoder) WriteU64List(values []uint64) error {
+	for _, v := range values {
+		if err := e.WriteU64(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close writes the serialized dictionary to the underlying io.Writer and
+// removes the temporary files. The first error is kept, so that later calls
+// to Close report it as well.
+func (e *Encoder) Close() error {
+	if e.closed {
+		return e.err
+	}
+	e.closed = true
+	defer e.cleanup()
+
+	// flush the presence bit words in progress
+	for l := range e.words {
+		if e.lens[l]&63 != 0 {
+			e.flushWord(l)
+		}
+	}
+	if e.err == nil {
+		e.err = e.copyOut()
+	}
+	return e.err
+}
+
+// copyOut writes the header and copies the spilled levels to the underlying
+// io.Writer.
+func (e *Encoder) copyOut() error {
+	var header [headerSize]byte
+	putHeader(header[:], 0, e.lens)
+	if _, err := e.w.Write(header[:]); err != nil {
+		return err
+	}
+
+	var zeros [8]byte
+	for l, n := range e.lens {
+		if err := e.chunks[l].copyTo(e.w); err != nil {
+			return err
+		}
+		if _, err := e.w.Write(zeros[:(8-n&7)&7]); err != nil {
+			return err
+		}
+
+		if l < nStreams64-1 {
+			if err := e.bitArr[l].copyTo(e.w); err != nil {
+				return err
+			}
+			if err := e.ranks[l].copyTo(e.w); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeByte appends a byte to level l.
+func (e *Encoder) writeByte(l int, b byte) {
+	if e.chunks[l] == nil {
+		e.chunks[l] = e.newSpill()
+	}
+	if e.err == nil {
+		e.err = e.chunks[l].w.WriteByte(b)
+	}
+}
+
+// advance completes the entry in progress at level l, which is not the last
+// level. The presence bit word is flushed when it is full.
+func (e *Encoder) advance(l int) {
+	e.lens[l]++
+	if e.lens[l]&63 == 0 {
+		e.flushWord(l)
+	}
+}
+
+// flushWord writes the presence bit word in progress of level l, preceded by
+// a rank when the word starts a new rank block.
+func (e *Encoder) flushWord(l int) {
+	if e.bitArr[l] == nil {
+		e.bitArr[l], e.ranks[l] = e.newSpill(), e.newSpill()
+	}
+	if e.err != nil {
+		return
+	}
+
+	if w := (e.lens[l] - 1) >> 6; w&7 == 0 {
+		binary.LittleEndian.PutUint64(e.buf[:], uint64(e.prefix[l]))
+		if _, e.err = e.ranks[l].w.Write(e.buf[:]); e.err != nil {
+			return
+		}
+	}
+
+	binary.LittleEndian.PutUint64(e.buf[:], e.words[l])
+	_, e.err = e.bitArr[l].w.Write(e.buf[:])
+
+	e.prefix[l] += bits.OnesCount64(e.words[l])
+	e.words[l] = 0
+}
+
+// newSpill creates a temporary file. Errors are recorded in e.err.
+func (e *Encoder) newSpill() *spill {
+	f, err := ioutil.TempFile(e.dir, "dac-")
+	if err != nil {
+		if e.err == nil {
+			e.err = err
+		}
+		return &spill{}
+	}
+	return &spill{f: f, w: bufio.NewWriter(f)}
+}
+
+// cleanup closes and removes all temporary files.
+func (e *Encoder) cleanup() {
+	remove := func(s *spill) {
+		if s != nil && s.f != nil {
+			s.f.Close()
+			os.Remove(s.f.Name())
+		}
+	}
+
+	for l := range e.chunks {
+		remove(e.chunks[l])
+	}
+	for l := range e.bitArr {
+		remove(e.bitArr[l])
+		remove(e.ranks[l])
+	}
+}
+
+// copyTo copies the contents of the temporary file to w. A nil spill is
+// empty.
+func (s *spill) copyTo(w io.Writer) error {
+	if s == nil {
+		return nil
+	}
+
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	if _, err := s.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.Copy(w, s.f)
+	return err
+}
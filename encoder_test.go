@@ -0,0 +1,88 @@
+package dac
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dac-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		files, _ := ioutil.ReadDir(dir)
+		if len(files) != 0 {
+			t.Errorf("%d temporary files left behind\n", len(files))
+		}
+	}()
+
+	for _, n := range []int{0, 1, 64, 511, 512, 513, 20_000} {
+		numbers := zipfNumbers(n, 15)
+
+		var got bytes.Buffer
+		e := NewEncoder(&got, dir)
+		for _, v := range numbers {
+			if err := e.WriteU64(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := e.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var want bytes.Buffer
+		From(numbers).WriteTo(&want)
+		if !bytes.Equal(got.Bytes(), want.Bytes()) {
+			t.Errorf("n: %d - encoder output differs from WriteTo\n", n)
+		}
+
+		var d Dict
+		if _, err := d.ReadFrom(&got); err != nil {
+			t.Fatal(err)
+		}
+		checkDict(t, &d, numbers)
+	}
+}
+
+func TestEncoderClosed(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf, "")
+	e.WriteU64List([]uint64{1, 1 << 40})
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.WriteU64(1); err == nil {
+		t.Error("expected error when writing to a closed encoder")
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	const n = 100_000
+
+	numbers := zipfNumbers(n, 15)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		e := NewEncoder(ioutil.Discard, "")
+		e.WriteU64List(numbers)
+		e.Close()
+	}
+}
+
+func TestEncoderCloseError(t *testing.T) {
+	for _, limit := range []int{0, headerSize + 10} {
+		e := NewEncoder(&failWriter{n: limit}, "")
+		e.WriteU64List(zipfNumbers(1_000, 15))
+
+		// repeated calls keep reporting the failure
+		for i := 0; i < 2; i++ {
+			if err := e.Close(); err != errWrite {
+				t.Errorf("limit %d - Close #%d - got: %v, want: %v\n", limit, i+1, err, errWrite)
+			}
+		}
+	}
+}
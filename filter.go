@@ -0,0 +1,94 @@
+package dac
+
+// Filter returns the bitmap of the indexes of the values for which pred is
+// true. pred is called once for each value of a single byte and for every
+// value of more than one byte. The dictionary must be closed.
+func (d *Dict) Filter(pred func(v uint64) bool) *Bitmap {
+	var small, wide [256]bool
+	for c := range small {
+		small[c] = pred(uint64(c))
+		wide[c] = true
+	}
+	return d.filter(&small, &wide, pred)
+}
+
+// FilterLess returns the bitmap of the indexes of the values smaller than x.
+// The dictionary must be closed.
+func (d *Dict) FilterLess(x uint64) *Bitmap {
+	return d.FilterRange(0, x)
+}
+
+// FilterRange returns the bitmap of the indexes of the values in the value
+// range [lo, hi). The dictionary must be closed.
+func (d *Dict) FilterRange(lo, hi uint64) *Bitmap {
+	pred := func(v uint64) bool { return lo <= v && v < hi }
+
+	var small, wide [256]bool
+	for c := range small {
+		small[c] = pred(uint64(c))
+	}
+
+	// values of more than one byte are at least 256
+	if 256 < hi {
+		for c := range wide {
+			wide[c] = true
+		}
+	}
+
+	return d.filter(&small, &wide, pred)
+}
+
+// FilterIn returns the bitmap of the indexes of the values in set. The
+// dictionary must be closed.
+func (d *Dict) FilterIn(set ...uint64) *Bitmap {
+	members := make(map[uint64]struct{}, len(set))
+	var small, wide [256]bool
+	for _, v := range set {
+		if v < 256 {
+			small[v] = true
+		} else {
+			members[v] = struct{}{}
+			wide[uint8(v)] = true
+		}
+	}
+
+	return d.filter(&small, &wide, func(v uint64) bool {
+		_, ok := members[v]
+		return ok
+	})
+}
+
+// filter returns the bitmap of the indexes of the matching values. The
+// values of a single byte are fully known from level 0, so small tells
+// whether they match. The values of more than one byte are only decoded when
+// wide holds their first byte, in which case pred tells whether they match.
+func (d *Dict) filter(small, wide *[256]bool, pred func(v uint64) bool) *Bitmap {
+	n := len(d.chunks[0])
+	b := NewBitmap(n)
+
+	var ranks [nStreams64 - 1]int
+	synced := false // whether ranks hold the decoding state at index k
+
+	for k, c := range d.chunks[0] {
+		if !d.bit(0, k) {
+			if small[c] {
+				b.words[k>>6] |= 1 << (k & 63)
+			}
+			continue
+		}
+
+		if !wide[c] {
+			synced = false
+			continue
+		}
+
+		if !synced {
+			ranks, synced = d.ranksAt(k), true
+		}
+		if pred(d.decode(k, &ranks)) {
+			b.words[k>>6] |= 1 << (k & 63)
+		}
+	}
+
+	return b
+}
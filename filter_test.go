@@ -0,0 +1,68 @@
+package dac
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func checkFilter(t *testing.T, name string, b *Bitmap, numbers []uint64, pred func(v uint64) bool) {
+	t.Helper()
+
+	want := make([]bool, len(numbers))
+	for i, v := range numbers {
+		want[i] = pred(v)
+	}
+	if b.Len() != len(numbers) {
+		t.Fatalf("%s - length: %d, want: %d\n", name, b.Len(), len(numbers))
+	}
+	for i, v := range want {
+		if b.Contains(i) != v {
+			t.Fatalf("%s - index %d (value %d): got: %v, want: %v\n", name, i, numbers[i], !v, v)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	numbers := zipfNumbers(5_000, 15)
+
+	d := From(numbers)
+
+	for _, x := range []uint64{0, 1, 100, 255, 256, 257, 70_000, 1 << 40} {
+		checkFilter(t, "FilterLess", d.FilterLess(x), numbers, func(v uint64) bool { return v < x })
+	}
+
+	for i := 0; i < 50; i++ {
+		lo, hi := numbers[r.Intn(len(numbers))], numbers[r.Intn(len(numbers))]
+		checkFilter(t, "FilterRange", d.FilterRange(lo, hi), numbers, func(v uint64) bool { return lo <= v && v < hi })
+	}
+
+	set := []uint64{0, 7, 255, 256, 511, numbers[10], numbers[4_000], 1 << 50}
+	checkFilter(t, "FilterIn", d.FilterIn(set...), numbers, func(v uint64) bool {
+		for _, w := range set {
+			if v == w {
+				return true
+			}
+		}
+		return false
+	})
+
+	even := func(v uint64) bool { return v&1 == 0 }
+	checkFilter(t, "Filter", d.Filter(even), numbers, even)
+
+	// combine filters
+	b := d.FilterLess(1_000)
+	b.AndNot(d.FilterIn(7))
+	checkFilter(t, "combined", b, numbers, func(v uint64) bool { return v < 1_000 && v != 7 })
+}
+
+func BenchmarkFilterLess(b *testing.B) {
+	d := From(zipfNumbers(10_000, 15))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.FilterLess(200)
+	}
+}
@@ -0,0 +1,191 @@
+package dac
+
+import (
+	"errors"
+	"math/bits"
+	"time"
+)
+
+// gatherBatch is the number of indexes that gather processes at once.
+const gatherBatch = 256
+
+// noEntry is a position far before any entry.
+const noEntry = -int(^uint(0) >> 2)
+
+// ReadU64At reads the uint64 values at the given indexes. One can avoid the
+// allocation of the return slice by supplying a slice of a size sufficient
+// to store all values. Supplying a slice is optional. The indexes are
+// validated before any value is read. The dictionary must be closed.
+//
+// The values are decoded level by level, so that the rank lookups of a level
+// follow each other. When the indexes are sorted, the rank of an index is
+// derived from the rank of its predecessor when they are close.
+func (d *Dict) ReadU64At(indexes []int, values []uint64) ([]uint64, error) {
+	if len(values) < len(indexes) {
+		values = make([]uint64, len(indexes))
+	} else {
+		values = values[:len(indexes)]
+	}
+
+	if err := d.checkIndexes(indexes); err != nil {
+		return values, err
+	}
+
+	for lo := 0; lo < len(indexes); lo += gatherBatch {
+		hi := lo + gatherBatch
+		if len(indexes) < hi {
+			hi = len(indexes)
+		}
+		d.gather(indexes[lo:hi], values[lo:hi])
+	}
+	return values, nil
+}
+
+// ReadI64At reads the int64 values at the given indexes. See ReadU64At.
+func (d *Dict) ReadI64At(indexes []int, values []int64) ([]int64, error) {
+	if len(values) < len(indexes) {
+		values = make([]int64, len(indexes))
+	} else {
+		values = values[:len(indexes)]
+	}
+
+	err := d.gatherEach(indexes, func(j int, uv uint64) {
+		values[j] = unzigzag(uv)
+	})
+	return values, err
+}
+
+// ReadFloat64At reads the float64 values at the given indexes. See
+// ReadU64At.
+func (d *Dict) ReadFloat64At(indexes []int, values []float64) ([]float64, error) {
+	if len(values) < len(indexes) {
+		values = make([]float64, len(indexes))
+	} else {
+		values = values[:len(indexes)]
+	}
+
+	err := d.gatherEach(indexes, func(j int, uv uint64) {
+		values[j] = unreverse64(uv)
+	})
+	return values, err
+}
+
+// ReadDateTimeAt reads the time.Time values at the given indexes. No
+// timezone is read. See ReadU64At.
+func (d *Dict) ReadDateTimeAt(indexes []int, dateTimes []time.Time) ([]time.Time, error) {
+	if len(dateTimes) < len(indexes) {
+		dateTimes = make([]time.Time, len(indexes))
+	} else {
+		dateTimes = dateTimes[:len(indexes)]
+	}
+
+	err := d.gatherEach(indexes, func(j int, uv uint64) {
+		dateTimes[j] = time.Unix(0, unzigzag(uv))
+	})
+	return dateTimes, err
+}
+
+// checkIndexes returns an error when an index is out of bounds.
+func (d *Dict) checkIndexes(indexes []int) error {
+	n := len(d.chunks[0])
+	for _, k := range indexes {
+		if k < 0 || n <= k {
+			return errors.New("dac: index k is out of bounds")
+		}
+	}
+	return nil
+}
+
+// gatherEach calls f with the position in indexes and the value of every
+// index, after validating the indexes.
+func (d *Dict) gatherEach(indexes []int, f func(j int, v uint64)) error {
+	if err := d.checkIndexes(indexes); err != nil {
+		return err
+	}
+
+	var vals [gatherBatch]uint64
+	for lo := 0; lo < len(indexes); lo += gatherBatch {
+		batch := indexes[lo:]
+		if len(batch) > gatherBatch {
+			batch = batch[:gatherBatch]
+		}
+
+		d.gather(batch, vals[:len(batch)])
+		for j, v := range vals[:len(batch)] {
+			f(lo+j, v)
+		}
+	}
+	return nil
+}
+
+// gather reads the values at the valid indexes of batch, which holds at most
+// gatherBatch indexes, into vals. The first byte of every value is read,
+// after which the values that continue are followed one level at a time.
+// The rank into the next level is computed while the presence bit is at
+// hand, so every level is visited once per value. When an entry follows
+// the previous one of its level closely, its rank is derived from the rank
+// of the previous one. The derivation is inlined, as it is performance
+// critical.
+func (d *Dict) gather(batch []int, vals []uint64) {
+	var (
+		pos    [gatherBatch]int    // position at the next level
+		active [gatherBatch]uint16 // batch offsets of the values that continue
+	)
+
+	// previous entry of the level and its rank, far away at the start
+	m := 0
+	prevK, prevRank := noEntry, 0
+	for j, k := range batch {
+		vals[j] = uint64(d.chunks[0][k])
+		if d.bit(0, k) {
+			r := 0
+			if uint(k-prevK) < 128 {
+				r = prevRank + d.countBits(0, prevK, k)
+			} else {
+				r = d.rank(0, k)
+			}
+			pos[j], prevK, prevRank = r, k, r
+			active[m] = uint16(j)
+			m++
+		}
+	}
+
+	for l := uint(1); m != 0; l++ {
+		prevK, prevRank = noEntry, 0
+
+		next := 0
+		for _, j := range active[:m] {
+			k := pos[j]
+			vals[j] |= uint64(d.chunks[l][k]) << (8 * l)
+
+			if l < nStreams64-1 && d.bit(l, k) {
+				r := 0
+				if uint(k-prevK) < 128 {
+					r = prevRank + d.countBits(l, prevK, k)
+				} else {
+					r = d.rank(l, k)
+				}
+				pos[j], prevK, prevRank = r, k, r
+				active[next] = j
+				next++
+			}
+		}
+		m = next
+	}
+}
+
+// countBits returns the number of bits set in level l at positions [lo, hi).
+func (d *Dict) countBits(l uint, lo, hi int) (count int) {
+	arr := d.bitArr[l]
+	for i := lo >> 6; i <= (hi-1)>>6 && lo < hi; i++ {
+		w := arr[i]
+		if i == lo>>6 {
+			w &= ^uint64(0) << (lo & 63)
+		}
+		if i == (hi-1)>>6 && hi&63 != 0 {
+			w &= 1<<(hi&63) - 1
+		}
+		count += bits.OnesCount64(w)
+	}
+	return
+}
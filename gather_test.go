@@ -0,0 +1,185 @@
+package dac
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestReadU64At(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+	numbers := zipfNumbers(20_000, 15)
+	d := From(numbers)
+
+	for _, m := range []int{0, 1, 255, 256, 257, 3_000} {
+		indexes := make([]int, m)
+		for i := range indexes {
+			indexes[i] = r.Intn(len(numbers))
+		}
+
+		for _, sorted := range []bool{false, true} {
+			if sorted {
+				sort.Ints(indexes)
+			}
+
+			got, err := d.ReadU64At(indexes, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != m {
+				t.Fatalf("m: %d - got %d values\n", m, len(got))
+			}
+			for i, k := range indexes {
+				if got[i] != numbers[k] {
+					t.Fatalf("m: %d, sorted: %v - index %d: got: %d, want: %d\n", m, sorted, k, got[i], numbers[k])
+				}
+			}
+		}
+	}
+
+	// dense and repeated indexes
+	indexes := make([]int, 0, 2*len(numbers))
+	for k := range numbers {
+		indexes = append(indexes, k, k)
+	}
+	got, err := d.ReadU64At(indexes, make([]uint64, len(indexes)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, k := range indexes {
+		if got[i] != numbers[k] {
+			t.Fatalf("dense - index %d: got: %d, want: %d\n", k, got[i], numbers[k])
+		}
+	}
+
+	if _, err := d.ReadU64At([]int{0, len(numbers)}, nil); err == nil {
+		t.Error("expected error for index beyond length")
+	}
+	if _, err := d.ReadU64At([]int{-1}, nil); err == nil {
+		t.Error("expected error for negative index")
+	}
+}
+
+func TestReadTypedAt(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	ints := make([]int64, 1_000)
+	floats := make([]float64, 1_000)
+	times := make([]time.Time, 1_000)
+	for i := range ints {
+		ints[i] = r.Int63() >> uint(r.Intn(63))
+		if r.Intn(2) == 1 {
+			ints[i] = -ints[i]
+		}
+		floats[i] = r.NormFloat64()
+		times[i] = time.Unix(0, ints[i])
+	}
+	floats[7] = math.Inf(-1)
+
+	indexes := make([]int, 500)
+	for i := range indexes {
+		indexes[i] = r.Intn(1_000)
+	}
+
+	d, _ := New()
+	d.WriteI64List(ints)
+	d.Close()
+	gotI, err := d.ReadI64At(indexes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotT, err := d.ReadDateTimeAt(indexes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.Reset()
+	d.WriteFloat64List(floats)
+	d.Close()
+	gotF, err := d.ReadFloat64At(append(indexes, 7), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotF[len(indexes)] != floats[7] {
+		t.Errorf("ReadFloat64At - got: %v, want: %v\n", gotF[len(indexes)], floats[7])
+	}
+
+	for i, k := range indexes {
+		if gotI[i] != ints[k] {
+			t.Fatalf("ReadI64At - index %d: got: %d, want: %d\n", k, gotI[i], ints[k])
+		}
+		if !gotT[i].Equal(times[k]) {
+			t.Fatalf("ReadDateTimeAt - index %d: got: %v, want: %v\n", k, gotT[i], times[k])
+		}
+		if gotF[i] != floats[k] {
+			t.Fatalf("ReadFloat64At - index %d: got: %v, want: %v\n", k, gotF[i], floats[k])
+		}
+	}
+}
+
+func TestCountBits(t *testing.T) {
+	d := From(zipfNumbers(2_000, 15))
+
+	for lo := 0; lo < 2_000; lo += 37 {
+		for hi := lo; hi <= 2_000; hi += 53 {
+			if got, want := d.countBits(0, lo, hi), d.rankAt(0, hi)-d.rankAt(0, lo); got != want {
+				t.Fatalf("countBits(0, %d, %d) - got: %d, want: %d\n", lo, hi, got, want)
+			}
+		}
+	}
+}
+
+// benchmarkReadAt reads m indexes out of n, which are clustered in a range of
+// 4*m entries when sorted.
+func benchmarkReadAt(b *testing.B, sorted bool) {
+	const n, m = 1_000_000, 4_096
+
+	d := From(zipfNumbers(n, 15))
+
+	r := rand.New(rand.NewSource(15))
+	indexes := make([]int, m)
+	for i := range indexes {
+		indexes[i] = r.Intn(n)
+		if sorted {
+			indexes[i] = n/2 + r.Intn(4*m)
+		}
+	}
+	if sorted {
+		sort.Ints(indexes)
+	}
+	values := make([]uint64, m)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.ReadU64At(indexes, values)
+	}
+}
+
+func BenchmarkReadU64At(b *testing.B)       { benchmarkReadAt(b, false) } // 70000 ns/op    0 B/op    0 allocs/op
+func BenchmarkReadU64AtSorted(b *testing.B) { benchmarkReadAt(b, true) }  // 51000 ns/op    0 B/op    0 allocs/op
+
+func BenchmarkReadU64Loop(b *testing.B) { // 80000 ns/op    0 B/op    0 allocs/op
+	const n, m = 1_000_000, 4_096
+
+	d := From(zipfNumbers(n, 15))
+
+	r := rand.New(rand.NewSource(15))
+	indexes := make([]int, m)
+	for i := range indexes {
+		indexes[i] = r.Intn(n)
+	}
+	values := make([]uint64, m)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j, k := range indexes {
+			values[j], _ = d.ReadU64(k)
+		}
+	}
+}
@@ -5,12 +5,34 @@ import (
 	"unsafe"
 )
 
+// ErrConcurrentModification is returned by the Err method of an iterator when
+// the layout of the dictionary changed during the iteration.
+var ErrConcurrentModification = errors.New("dac: dictionary modified during iteration")
+
 // Iterator enables iteration over the dictionary. Concurrent iteration
 // is allowed once the dictionary is closed (no more writing).
+//
+// The state of an iterator refers to positions in the levels of the
+// dictionary. Operations that change the layout of the levels invalidate
+// it: InsertU64At, PrependU64, RemoveAt, Reset, ReadFrom, the bulk insert,
+// remove and update operations, and an UpdateU64At that changes the byte
+// length of the value. The iterator detects these changes: Next, Prev and
+// Peek then return no more values and Err returns
+// ErrConcurrentModification. Seek and Reset make the iterator valid again.
+// The range of an iterator from IterRange is then clamped to the length of
+// the dictionary.
+//
+// The other writes are iterator-safe: an UpdateU64At that keeps the byte
+// length of the value, the Write functions and Append, which add values at
+// the end, as well as Close and Compact.
 type Iterator struct {
 	d     *Dict               // pointer to dictionary
 	ranks [nStreams64 - 1]int // rank (starting to count from 0)
 	k     int                 // current index
+	lo    int                 // first index of the range
+	hi    int                 // end of the range, or -1 for the end of the dictionary
+	mods  uint64              // modification counter of the dictionary at the last Seek
+	err   error               // set when the dictionary was modified
 }
 
 // NewIterator creates an iterator for the given dictionary.
@@ -26,35 +48,57 @@ func (d *Dict) Iter() Iterator {
 	return Iterator{
 		d:     d,
 		ranks: [nStreams64 - 1]int{-1, -1, -1, -1, -1, -1, -1},
+		hi:    -1,
+		mods:  d.mods,
 	}
 }
 
+// IterRange creates an iterator over the index range [lo, hi) of the
+// dictionary. Next stops at hi and Prev stops at lo. The dictionary must be
+// closed.
+func (d *Dict) IterRange(lo, hi int) (Iterator, error) {
+	if lo < 0 || hi < lo || len(d.chunks[0]) < hi {
+		return Iterator{}, errors.New("dac: index range is out of bounds")
+	}
+
+	it := Iterator{d: d, lo: lo, hi: hi}
+	it.Reset()
+	return it, nil
+}
+
 // Value returns the k-th value from the dictionary. It also sets the iterator
 // state, so that subsequent calls to Next will return the k+1, k+2, ... value.
+// The dictionary must be closed.
 func (it *Iterator) Value(k int) (v uint64, err error) {
-	if k < 0 || len(it.d.chunks[0]) <= k {
+	if k < it.lo || it.end() <= k {
 		return 0, errors.New("dac: key k is out of bounds")
 	}
 
-	buf := (*[nStreams64]byte)(unsafe.Pointer(&v))
-	buf[0] = it.d.chunks[0][k]
+	it.Seek(k)
+	_, v, _ = it.Next()
+	return
+}
 
-	var l uint
-	for l < nStreams64-1 && it.d.bit(l, k) {
-		k = it.d.rank(l, k)
-		it.ranks[l] = k
-		l++
-		buf[l] = it.d.chunks[l][k]
+// Seek positions the iterator, so that the next call to Next returns the
+// k-th value and the next call to Prev the (k-1)-th value. k may be equal to
+// the end of the range. The dictionary must be closed.
+func (it *Iterator) Seek(k int) error {
+	it.clamp()
+	if k < it.lo || it.end() < k {
+		return errors.New("dac: key k is out of bounds")
 	}
 
-	return
+	it.k = k
+	it.ranks = it.d.ranksAt(k)
+	it.mods, it.err = it.d.mods, nil
+	return nil
 }
 
 // Next returns the next index and value from the dictionary.
 // If there is not a next value, the ok return value will be false.
 func (it *Iterator) Next() (k int, v uint64, ok bool) {
 	i, k := it.k, it.k // Needs to be more transparent! and faster!
-	if ok = (i < Len(it.d)); !ok {
+	if ok = it.valid() && i < it.end(); !ok {
 		return
 	}
 
@@ -73,9 +117,117 @@ func (it *Iterator) Next() (k int, v uint64, ok bool) {
 	return
 }
 
+// NextBatch decodes the next values into out and returns their number. It
+// returns fewer than len(out) values only at the end of the range, or when
+// the layout of the dictionary changed. Runs of 64 values without a
+// continuation bit at level 0 are copied from level 0 in one go.
+func (it *Iterator) NextBatch(out []uint64) int {
+	if !it.valid() {
+		return 0
+	}
+
+	d, k := it.d, it.k
+	if n := it.end() - k; n < len(out) {
+		out = out[:n]
+	}
+
+	for i := 0; i < len(out); {
+		if k&63 == 0 && 64 <= len(out)-i && d.bitArr[0][k>>6] == 0 {
+			dst := out[i : i+64]
+			for j, c := range d.chunks[0][k : k+64] {
+				dst[j] = uint64(c)
+			}
+			i, k = i+64, k+64
+			continue
+		}
+
+		out[i] = d.decode(k, &it.ranks)
+		i, k = i+1, k+1
+	}
+
+	it.k = k
+	return len(out)
+}
+
+// Prev returns the previous index and value from the dictionary and moves
+// the iterator backwards, so that Prev and Next can be mixed. If there is not
+// a previous value, the ok return value will be false.
+func (it *Iterator) Prev() (k int, v uint64, ok bool) {
+	if ok = it.valid() && it.lo < it.k; !ok {
+		return
+	}
+
+	it.k--
+	i, k := it.k, it.k
+	buf := (*[nStreams64]byte)(unsafe.Pointer(&v))
+	buf[0] = it.d.chunks[0][i]
+
+	// the entries of the k-th value are the last ones consumed
+	var j uint
+	for j < nStreams64-1 && it.d.bit(j, i) {
+		i = it.ranks[j]
+		it.ranks[j]--
+		j++
+		buf[j] = it.d.chunks[j][i]
+	}
+
+	return
+}
+
+// Peek returns the next index and value from the dictionary, like Next, but
+// without advancing the iterator.
+func (it *Iterator) Peek() (k int, v uint64, ok bool) {
+	next, ranks := it.k, it.ranks
+	k, v, ok = it.Next()
+	it.k, it.ranks = next, ranks
+	return
+}
+
 // Reset resets the iterator, without releasing its resources. After Reset,
-// the iterator points again to the first element of the dictionary.
+// the iterator points again to the first element of its range.
 func (it *Iterator) Reset() {
-	it.k = 0
-	it.ranks = [nStreams64 - 1]int{-1, -1, -1, -1, -1, -1, -1}
+	it.clamp()
+	it.k = it.lo
+	it.mods, it.err = it.d.mods, nil
+	if it.lo == 0 {
+		it.ranks = [nStreams64 - 1]int{-1, -1, -1, -1, -1, -1, -1}
+	} else {
+		it.ranks = it.d.ranksAt(it.lo)
+	}
+}
+
+// Err returns ErrConcurrentModification when the iteration stopped because
+// the layout of the dictionary changed.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// valid reports whether the layout of the dictionary is unchanged since the
+// iterator was positioned. If not, the error is recorded.
+func (it *Iterator) valid() bool {
+	if it.mods != it.d.mods {
+		it.err = ErrConcurrentModification
+		return false
+	}
+	return true
+}
+
+// clamp shrinks the range of the iterator to the length of the dictionary,
+// which may have shrunk since the range was set.
+func (it *Iterator) clamp() {
+	n := len(it.d.chunks[0])
+	if n < it.hi {
+		it.hi = n
+	}
+	if n < it.lo {
+		it.lo = n
+	}
+}
+
+// end returns the end of the range of the iterator.
+func (it *Iterator) end() int {
+	if it.hi < 0 {
+		return len(it.d.chunks[0])
+	}
+	return it.hi
 }
@@ -100,3 +100,398 @@ func BenchmarkIteratorNext(b *testing.B) { // 3.57 ns/op    0 B/op    0 allocs/o
 		it.Reset()
 	}
 }
+
+func TestIteratorSeekPrevPeek(t *testing.T) {
+	numbers := zipfNumbers(3_000, 15)
+	d := From(numbers)
+	it := d.Iter()
+
+	for _, k := range []int{0, 1, 511, 1_500, 2_999, 3_000} {
+		if err := it.Seek(k); err != nil {
+			t.Fatal(err)
+		}
+
+		if k < len(numbers) {
+			if i, v, ok := it.Peek(); !ok || i != k || v != numbers[k] {
+				t.Fatalf("Peek after Seek(%d) - got: (%d, %d, %v)\n", k, i, v, ok)
+			}
+		}
+
+		// forward to the end
+		for i := k; i < len(numbers); i++ {
+			j, v, ok := it.Next()
+			if !ok || j != i || v != numbers[i] {
+				t.Fatalf("Next after Seek(%d) - got: (%d, %d, %v), want: (%d, %d)\n", k, j, v, ok, i, numbers[i])
+			}
+		}
+		if _, _, ok := it.Next(); ok {
+			t.Fatal("Next beyond the end")
+		}
+		if _, _, ok := it.Peek(); ok {
+			t.Fatal("Peek beyond the end")
+		}
+
+		// back to the start
+		for i := len(numbers) - 1; 0 <= i; i-- {
+			j, v, ok := it.Prev()
+			if !ok || j != i || v != numbers[i] {
+				t.Fatalf("Prev - got: (%d, %d, %v), want: (%d, %d)\n", j, v, ok, i, numbers[i])
+			}
+		}
+		if _, _, ok := it.Prev(); ok {
+			t.Fatal("Prev before the start")
+		}
+	}
+
+	// mixing directions
+	it.Seek(100)
+	it.Next()
+	it.Next()
+	it.Prev()
+	if j, v, ok := it.Next(); !ok || j != 101 || v != numbers[101] {
+		t.Errorf("Next after Prev - got: (%d, %d, %v), want: (101, %d)\n", j, v, ok, numbers[101])
+	}
+
+	if err := it.Seek(3_001); err == nil {
+		t.Error("expected error for Seek beyond the end")
+	}
+
+	if v, err := it.Value(42); err != nil || v != numbers[42] {
+		t.Errorf("Value(42) - got: %d, want: %d, err: %v\n", v, numbers[42], err)
+	}
+	if j, v, ok := it.Next(); !ok || j != 43 || v != numbers[43] {
+		t.Errorf("Next after Value(42) - got: (%d, %d, %v), want: (43, %d)\n", j, v, ok, numbers[43])
+	}
+}
+
+func TestIterRange(t *testing.T) {
+	numbers := zipfNumbers(3_000, 15)
+	d := From(numbers)
+
+	it, err := d.IterRange(700, 1_300)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for pass := 0; pass < 2; pass++ {
+		i := 700
+		for k, v, ok := it.Next(); ok; k, v, ok = it.Next() {
+			if k != i || v != numbers[i] {
+				t.Fatalf("pass %d - got: (%d, %d), want: (%d, %d)\n", pass, k, v, i, numbers[i])
+			}
+			i++
+		}
+		if i != 1_300 {
+			t.Fatalf("pass %d - stopped at %d, want: 1300\n", pass, i)
+		}
+
+		for k, _, ok := it.Prev(); ok; k, _, ok = it.Prev() {
+			i = k
+		}
+		if i != 700 {
+			t.Fatalf("pass %d - Prev stopped at %d, want: 700\n", pass, i)
+		}
+		it.Seek(1_000)
+		it.Reset()
+	}
+
+	if err := it.Seek(699); err == nil {
+		t.Error("expected error for Seek before the range")
+	}
+	if _, err := it.Value(1_300); err == nil {
+		t.Error("expected error for Value beyond the range")
+	}
+	if _, err := d.IterRange(10, 3_001); err == nil {
+		t.Error("expected error for range beyond length")
+	}
+
+	empty, err := d.IterRange(5, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := empty.Next(); ok {
+		t.Error("Next on an empty range")
+	}
+}
+
+func BenchmarkIteratorPrev(b *testing.B) {
+	const n = 1_000
+
+	d := From(zipfNumbers(n, 15))
+	it := d.Iter()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		it.Seek(n)
+		for j := 0; j < n; j++ {
+			it.Prev()
+		}
+	}
+}
+
+func TestIteratorConcurrentModification(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(d *Dict)
+		safe   bool
+	}{
+		{"InsertU64At", func(d *Dict) { d.InsertU64At(5, 1) }, false},
+		{"PrependU64", func(d *Dict) { d.PrependU64(1 << 20) }, false},
+		{"RemoveAt", func(d *Dict) { d.RemoveAt(5) }, false},
+		{"UpdateU64At longer", func(d *Dict) { d.UpdateU64At(5, 1<<40) }, false},
+		{"UpdateU64At shorter", func(d *Dict) { d.UpdateU64At(6, 1) }, false},
+		{"RemoveRange", func(d *Dict) { d.RemoveRange(3, 8) }, false},
+		{"RemoveIndexes", func(d *Dict) { d.RemoveIndexes([]int{1, 2}) }, false},
+		{"InsertU64ListAt", func(d *Dict) { d.InsertU64ListAt(3, []uint64{1, 2}) }, false},
+		{"UpdateU64Range", func(d *Dict) { d.UpdateU64Range(3, []uint64{1, 2}) }, false},
+		{"Reset", func(d *Dict) { d.Reset() }, false},
+		{"UpdateU64At same length", func(d *Dict) { d.UpdateU64At(5, 0x1234) }, true},
+		{"WriteU64", func(d *Dict) { d.WriteU64(1 << 30); d.Close() }, true},
+		{"Append", func(d *Dict) { d.Append(From([]uint64{1, 1 << 50})) }, true},
+		{"Compact", func(d *Dict) { d.Compact() }, true},
+		{"RemoveRange empty", func(d *Dict) { d.RemoveRange(3, 3) }, true},
+	}
+
+	for _, tt := range tests {
+		numbers := []uint64{1, 2, 3, 4, 5, 0x4321, 1 << 16, 8, 9, 10, 11, 12}
+		d := From(numbers)
+
+		it := d.Iter()
+		it.Next()
+		it.Next()
+		rit, err := d.IterRange(0, len(numbers))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rit.Next()
+		tt.modify(d)
+
+		_, _, ok := it.Next()
+		if tt.safe {
+			if !ok || it.Err() != nil {
+				t.Errorf("%s - iteration stopped, err: %v\n", tt.name, it.Err())
+				continue
+			}
+
+			// the remaining values are the current ones
+			want := d.ReadU64List(nil)
+			for k, v, ok := it.Next(); ok; k, v, ok = it.Next() {
+				if v != want[k] {
+					t.Errorf("%s - index %d: got: %d, want: %d\n", tt.name, k, v, want[k])
+				}
+			}
+			continue
+		}
+
+		if ok || it.Err() != ErrConcurrentModification {
+			t.Errorf("%s - got ok: %v, err: %v, want: %v\n", tt.name, ok, it.Err(), ErrConcurrentModification)
+		}
+		if _, _, ok := it.Prev(); ok {
+			t.Errorf("%s - Prev after a modification\n", tt.name)
+		}
+
+		// Seek revalidates the iterator
+		if Len(d) != 0 {
+			d.Close()
+			if err := it.Seek(0); err != nil {
+				t.Fatal(err)
+			}
+			if k, v, ok := it.Next(); !ok || it.Err() != nil || v != d.ReadU64List(nil)[k] {
+				t.Errorf("%s - Next after Seek - got: (%d, %d, %v), err: %v\n", tt.name, k, v, ok, it.Err())
+			}
+
+			// the range is clamped to the new length
+			if err := rit.Seek(0); err != nil {
+				t.Fatal(err)
+			}
+			want := d.ReadU64List(nil)
+			n := 0
+			for k, v, ok := rit.Next(); ok; k, v, ok = rit.Next() {
+				if k < len(want) && v != want[k] {
+					t.Errorf("%s - range index %d: got: %d, want: %d\n", tt.name, k, v, want[k])
+				}
+				n++
+			}
+			end := len(numbers)
+			if len(want) < end {
+				end = len(want)
+			}
+			if n != end {
+				t.Errorf("%s - range got %d values, want: %d\n", tt.name, n, end)
+			}
+		}
+	}
+}
+
+func TestIterRangeConcurrentModification(t *testing.T) {
+	numbers := []uint64{1, 2, 3, 1 << 20, 5, 6, 1 << 40, 8, 9, 10, 11, 12}
+
+	for _, seek := range []bool{true, false} {
+		d := From(numbers)
+		it, err := d.IterRange(2, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		it.Next()
+
+		// the dictionary shrinks below the end of the range
+		d.RemoveRange(0, 5)
+		d.Close()
+		want := d.ReadU64List(nil)
+
+		if seek {
+			if err := it.Seek(8); err == nil {
+				t.Error("Seek beyond the clamped range - expected an error")
+			}
+			if err := it.Seek(2); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			it.Reset()
+		}
+
+		var got []uint64
+		for k, v, ok := it.Next(); ok; k, v, ok = it.Next() {
+			if v != want[k] {
+				t.Errorf("index %d: got: %d, want: %d\n", k, v, want[k])
+			}
+			got = append(got, v)
+		}
+		if len(got) != len(want)-2 || it.Err() != nil {
+			t.Errorf("seek: %v - got %d values, err: %v\n", seek, len(got), it.Err())
+		}
+
+		it.Reset()
+		out := make([]uint64, 20)
+		if m := it.NextBatch(out); m != len(want)-2 {
+			t.Errorf("seek: %v - NextBatch got %d values\n", seek, m)
+		}
+
+		// a range that starts beyond the new length becomes empty
+		d.RemoveRange(0, 6)
+		d.Close()
+		it.Reset()
+		if _, _, ok := it.Next(); ok {
+			t.Errorf("seek: %v - Next in an empty range\n", seek)
+		}
+	}
+}
+
+func TestTypedIteratorConcurrentModification(t *testing.T) {
+	d, _ := New()
+	d.WriteI64List([]int64{-1, 2, -3, 4})
+	d.Close()
+
+	it, it8 := d.IterI64(), d.IterI8()
+	it.Next()
+	it8.Next()
+	d.RemoveAt(0)
+
+	if _, _, ok := it.Next(); ok || it.Err() != ErrConcurrentModification {
+		t.Errorf("I64Iterator - got ok: %v, err: %v\n", ok, it.Err())
+	}
+	if _, _, ok := it8.Next(); ok || it8.Err() != ErrConcurrentModification {
+		t.Errorf("I8Iterator - got ok: %v, err: %v\n", ok, it8.Err())
+	}
+}
+
+func TestIteratorNextBatch(t *testing.T) {
+	const n = 1_000
+
+	// runs of single byte values, interleaved with wider values
+	r := rand.New(rand.NewSource(15))
+	numbers := make([]uint64, n)
+	for i := range numbers {
+		numbers[i] = uint64(r.Intn(256))
+		if 300 <= i && i < 700 && r.Intn(4) == 0 {
+			numbers[i] = r.Uint64() >> uint(r.Intn(64))
+		}
+	}
+	d := From(numbers)
+
+	for _, size := range []int{1, 63, 64, 65, 200, 1024} {
+		it := d.Iter()
+		out := make([]uint64, size)
+		var got []uint64
+		for m := it.NextBatch(out); m != 0; m = it.NextBatch(out) {
+			got = append(got, out[:m]...)
+		}
+		if len(got) != n || it.Err() != nil {
+			t.Errorf("size %d - got %d values, err: %v\n", size, len(got), it.Err())
+			continue
+		}
+		for k, v := range got {
+			if v != numbers[k] {
+				t.Errorf("size %d - index %d: got: %d, want: %d\n", size, k, v, numbers[k])
+				break
+			}
+		}
+	}
+
+	// mixed with Next and Prev, within a range
+	it, err := d.IterRange(10, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+	it.Next()
+	out := make([]uint64, 500)
+	if m := it.NextBatch(out[:100]); m != 100 || out[0] != numbers[11] || out[99] != numbers[110] {
+		t.Errorf("NextBatch - got %d values\n", m)
+	}
+	if k, v, ok := it.Prev(); !ok || k != 110 || v != numbers[110] {
+		t.Errorf("Prev after NextBatch - got: (%d, %d, %v)\n", k, v, ok)
+	}
+	it.Next()
+	if m := it.NextBatch(out); m != 500 || out[499] != numbers[610] {
+		t.Errorf("NextBatch - got %d values\n", m)
+	}
+	if m := it.NextBatch(out); m != 289 || out[288] != numbers[899] {
+		t.Errorf("NextBatch at the end of the range - got %d values\n", m)
+	}
+	if k, v, ok := it.Next(); ok {
+		t.Errorf("Next after the range - got: (%d, %d)\n", k, v)
+	}
+
+	it = d.Iter()
+	d.RemoveAt(0)
+	if m := it.NextBatch(out); m != 0 || it.Err() != ErrConcurrentModification {
+		t.Errorf("NextBatch after a modification - got %d values, err: %v\n", m, it.Err())
+	}
+}
+
+func BenchmarkIteratorNextBatch(b *testing.B) {
+	const n = 10_000
+
+	r := rand.New(rand.NewSource(15))
+	zipf := rand.NewZipf(r, 1.15, 1, math.MaxUint64)
+	numbers, small := make([]uint64, n), make([]uint64, n)
+	for i := range numbers {
+		numbers[i] = zipf.Uint64()
+		small[i] = uint64(r.Intn(256))
+	}
+	out := make([]uint64, 256)
+
+	for _, bm := range []struct {
+		name string
+		d    *Dict
+	}{{"Zipf", From(numbers)}, {"Small", From(small)}} {
+		d := bm.d
+		b.Run(bm.name+"/Next", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				it := d.Iter()
+				for _, _, ok := it.Next(); ok; _, _, ok = it.Next() {
+				}
+			}
+		})
+
+		b.Run(bm.name+"/NextBatch", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				it := d.Iter()
+				for it.NextBatch(out) != 0 {
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,708 @@
+package dac
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// The typed iterators wrap an Iterator and return the values decoded like
+// the corresponding Read function. The iterators for types narrower than 64
+// bits stop at the first value that is too wide for their type. Their Err
+// method then returns an *OverflowError holding its index. Like Iterator,
+// all typed iterators detect changes to the layout of the dictionary.
+
+// I64Iterator enables iteration over a dictionary of int64 values.
+type I64Iterator struct {
+	it Iterator
+}
+
+// IterI64 creates an iterator for a dictionary of int64 values.
+func (d *Dict) IterI64() I64Iterator {
+	return I64Iterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, the ok return value will be false.
+func (it *I64Iterator) Next() (k int, v int64, ok bool) {
+	k, uv, ok := it.it.Next()
+	return k, unzigzag(uv), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. See
+// Iterator.NextBatch.
+func (it *I64Iterator) NextBatch(out []int64) (n int) {
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			out[n+i] = unzigzag(uv)
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. See Iterator.Value.
+func (it *I64Iterator) Value(k int) (int64, error) {
+	uv, err := it.it.Value(k)
+	return unzigzag(uv), err
+}
+
+// Err returns ErrConcurrentModification when the iteration stopped because
+// the layout of the dictionary changed.
+func (it *I64Iterator) Err() error {
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *I64Iterator) Reset() {
+	it.it.Reset()
+}
+
+// Float32Iterator enables iteration over a dictionary of float32 values.
+type Float32Iterator struct {
+	it Iterator
+}
+
+// IterFloat32 creates an iterator for a dictionary of float32 values.
+func (d *Dict) IterFloat32() Float32Iterator {
+	return Float32Iterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, the ok return value will be false.
+func (it *Float32Iterator) Next() (k int, v float32, ok bool) {
+	k, uv, ok := it.it.Next()
+	return k, math.Float32frombits(bits.ReverseBytes32(uint32(uv))), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. See
+// Iterator.NextBatch.
+func (it *Float32Iterator) NextBatch(out []float32) (n int) {
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			out[n+i] = math.Float32frombits(bits.ReverseBytes32(uint32(uv)))
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. See Iterator.Value.
+func (it *Float32Iterator) Value(k int) (float32, error) {
+	uv, err := it.it.Value(k)
+	return math.Float32frombits(bits.ReverseBytes32(uint32(uv))), err
+}
+
+// Err returns ErrConcurrentModification when the iteration stopped because
+// the layout of the dictionary changed.
+func (it *Float32Iterator) Err() error {
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *Float32Iterator) Reset() {
+	it.it.Reset()
+}
+
+// Float64Iterator enables iteration over a dictionary of float64 values.
+type Float64Iterator struct {
+	it Iterator
+}
+
+// IterFloat64 creates an iterator for a dictionary of float64 values.
+func (d *Dict) IterFloat64() Float64Iterator {
+	return Float64Iterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, the ok return value will be false.
+func (it *Float64Iterator) Next() (k int, v float64, ok bool) {
+	k, uv, ok := it.it.Next()
+	return k, unreverse64(uv), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. See
+// Iterator.NextBatch.
+func (it *Float64Iterator) NextBatch(out []float64) (n int) {
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			out[n+i] = unreverse64(uv)
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. See Iterator.Value.
+func (it *Float64Iterator) Value(k int) (float64, error) {
+	uv, err := it.it.Value(k)
+	return unreverse64(uv), err
+}
+
+// Err returns ErrConcurrentModification when the iteration stopped because
+// the layout of the dictionary changed.
+func (it *Float64Iterator) Err() error {
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *Float64Iterator) Reset() {
+	it.it.Reset()
+}
+
+// DateTimeIterator enables iteration over a dictionary of time.Time values.
+type DateTimeIterator struct {
+	it Iterator
+}
+
+// IterDateTime creates an iterator for a dictionary of time.Time values. No timezone is read.
+func (d *Dict) IterDateTime() DateTimeIterator {
+	return DateTimeIterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, the ok return value will be false.
+func (it *DateTimeIterator) Next() (k int, v time.Time, ok bool) {
+	k, uv, ok := it.it.Next()
+	return k, time.Unix(0, unzigzag(uv)), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. See
+// Iterator.NextBatch.
+func (it *DateTimeIterator) NextBatch(out []time.Time) (n int) {
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			out[n+i] = time.Unix(0, unzigzag(uv))
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. See Iterator.Value.
+func (it *DateTimeIterator) Value(k int) (time.Time, error) {
+	uv, err := it.it.Value(k)
+	return time.Unix(0, unzigzag(uv)), err
+}
+
+// Err returns ErrConcurrentModification when the iteration stopped because
+// the layout of the dictionary changed.
+func (it *DateTimeIterator) Err() error {
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *DateTimeIterator) Reset() {
+	it.it.Reset()
+}
+
+// BoolIterator enables iteration over a dictionary of boolean values.
+type BoolIterator struct {
+	it Iterator
+}
+
+// IterBool creates an iterator for a dictionary of boolean values.
+func (d *Dict) IterBool() BoolIterator {
+	return BoolIterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, the ok return value will be false.
+func (it *BoolIterator) Next() (k int, v bool, ok bool) {
+	k, uv, ok := it.it.Next()
+	return k, uv != 0, ok
+}
+
+// NextBatch decodes the next values into out and returns their number. See
+// Iterator.NextBatch.
+func (it *BoolIterator) NextBatch(out []bool) (n int) {
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			out[n+i] = uv != 0
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. See Iterator.Value.
+func (it *BoolIterator) Value(k int) (bool, error) {
+	uv, err := it.it.Value(k)
+	return uv != 0, err
+}
+
+// Err returns ErrConcurrentModification when the iteration stopped because
+// the layout of the dictionary changed.
+func (it *BoolIterator) Err() error {
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *BoolIterator) Reset() {
+	it.it.Reset()
+}
+
+// U8Iterator enables iteration over a dictionary of uint8 values.
+type U8Iterator struct {
+	it  Iterator
+	err error
+}
+
+// IterU8 creates an iterator for a dictionary of uint8 values.
+func (d *Dict) IterU8() U8Iterator {
+	return U8Iterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, or the next value overflows uint8, the ok return value will
+// be false.
+func (it *U8Iterator) Next() (k int, v uint8, ok bool) {
+	k, uv, ok := it.it.nextNarrow(math.MaxUint8, &it.err)
+	return k, uint8(uv), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. It
+// stops before a value that overflows uint8. See Iterator.NextBatch.
+func (it *U8Iterator) NextBatch(out []uint8) (n int) {
+	if it.err != nil {
+		return 0
+	}
+
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			if uv > math.MaxUint8 {
+				it.err = &OverflowError{Index: it.it.k - m + i}
+				return n + i
+			}
+			out[n+i] = uint8(uv)
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. When the value is too
+// wide for uint8, its low-order bytes are returned together with
+// ErrOverflow. See Iterator.Value.
+func (it *U8Iterator) Value(k int) (uint8, error) {
+	uv, err := it.it.Value(k)
+	if err == nil && uv > math.MaxUint8 {
+		err = ErrOverflow
+	}
+	return uint8(uv), err
+}
+
+// Err returns an *OverflowError when the iteration stopped at a value that
+// overflows uint8, or ErrConcurrentModification when the layout of the
+// dictionary changed.
+func (it *U8Iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *U8Iterator) Reset() {
+	it.it.Reset()
+	it.err = nil
+}
+
+// U16Iterator enables iteration over a dictionary of uint16 values.
+type U16Iterator struct {
+	it  Iterator
+	err error
+}
+
+// IterU16 creates an iterator for a dictionary of uint16 values.
+func (d *Dict) IterU16() U16Iterator {
+	return U16Iterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, or the next value overflows uint16, the ok return value will
+// be false.
+func (it *U16Iterator) Next() (k int, v uint16, ok bool) {
+	k, uv, ok := it.it.nextNarrow(math.MaxUint16, &it.err)
+	return k, uint16(uv), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. It
+// stops before a value that overflows uint16. See Iterator.NextBatch.
+func (it *U16Iterator) NextBatch(out []uint16) (n int) {
+	if it.err != nil {
+		return 0
+	}
+
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			if uv > math.MaxUint16 {
+				it.err = &OverflowError{Index: it.it.k - m + i}
+				return n + i
+			}
+			out[n+i] = uint16(uv)
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. When the value is too
+// wide for uint16, its low-order bytes are returned together with
+// ErrOverflow. See Iterator.Value.
+func (it *U16Iterator) Value(k int) (uint16, error) {
+	uv, err := it.it.Value(k)
+	if err == nil && uv > math.MaxUint16 {
+		err = ErrOverflow
+	}
+	return uint16(uv), err
+}
+
+// Err returns an *OverflowError when the iteration stopped at a value that
+// overflows uint16, or ErrConcurrentModification when the layout of the
+// dictionary changed.
+func (it *U16Iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *U16Iterator) Reset() {
+	it.it.Reset()
+	it.err = nil
+}
+
+// U32Iterator enables iteration over a dictionary of uint32 values.
+type U32Iterator struct {
+	it  Iterator
+	err error
+}
+
+// IterU32 creates an iterator for a dictionary of uint32 values.
+func (d *Dict) IterU32() U32Iterator {
+	return U32Iterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, or the next value overflows uint32, the ok return value will
+// be false.
+func (it *U32Iterator) Next() (k int, v uint32, ok bool) {
+	k, uv, ok := it.it.nextNarrow(math.MaxUint32, &it.err)
+	return k, uint32(uv), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. It
+// stops before a value that overflows uint32. See Iterator.NextBatch.
+func (it *U32Iterator) NextBatch(out []uint32) (n int) {
+	if it.err != nil {
+		return 0
+	}
+
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			if uv > math.MaxUint32 {
+				it.err = &OverflowError{Index: it.it.k - m + i}
+				return n + i
+			}
+			out[n+i] = uint32(uv)
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. When the value is too
+// wide for uint32, its low-order bytes are returned together with
+// ErrOverflow. See Iterator.Value.
+func (it *U32Iterator) Value(k int) (uint32, error) {
+	uv, err := it.it.Value(k)
+	if err == nil && uv > math.MaxUint32 {
+		err = ErrOverflow
+	}
+	return uint32(uv), err
+}
+
+// Err returns an *OverflowError when the iteration stopped at a value that
+// overflows uint32, or ErrConcurrentModification when the layout of the
+// dictionary changed.
+func (it *U32Iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *U32Iterator) Reset() {
+	it.it.Reset()
+	it.err = nil
+}
+
+// I8Iterator enables iteration over a dictionary of int8 values.
+type I8Iterator struct {
+	it  Iterator
+	err error
+}
+
+// IterI8 creates an iterator for a dictionary of int8 values.
+func (d *Dict) IterI8() I8Iterator {
+	return I8Iterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, or the next value overflows int8, the ok return value will
+// be false.
+func (it *I8Iterator) Next() (k int, v int8, ok bool) {
+	k, uv, ok := it.it.nextNarrow(math.MaxUint8, &it.err)
+	return k, int8(unzigzag(uv)), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. It
+// stops before a value that overflows int8. See Iterator.NextBatch.
+func (it *I8Iterator) NextBatch(out []int8) (n int) {
+	if it.err != nil {
+		return 0
+	}
+
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			if uv > math.MaxUint8 {
+				it.err = &OverflowError{Index: it.it.k - m + i}
+				return n + i
+			}
+			out[n+i] = int8(unzigzag(uv))
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. When the value is too
+// wide for int8, its low-order bytes are returned together with
+// ErrOverflow. See Iterator.Value.
+func (it *I8Iterator) Value(k int) (int8, error) {
+	uv, err := it.it.Value(k)
+	if err == nil && uv > math.MaxUint8 {
+		err = ErrOverflow
+	}
+	return int8(unzigzag(uv)), err
+}
+
+// Err returns an *OverflowError when the iteration stopped at a value that
+// overflows int8, or ErrConcurrentModification when the layout of the
+// dictionary changed.
+func (it *I8Iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *I8Iterator) Reset() {
+	it.it.Reset()
+	it.err = nil
+}
+
+// I16Iterator enables iteration over a dictionary of int16 values.
+type I16Iterator struct {
+	it  Iterator
+	err error
+}
+
+// IterI16 creates an iterator for a dictionary of int16 values.
+func (d *Dict) IterI16() I16Iterator {
+	return I16Iterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, or the next value overflows int16, the ok return value will
+// be false.
+func (it *I16Iterator) Next() (k int, v int16, ok bool) {
+	k, uv, ok := it.it.nextNarrow(math.MaxUint16, &it.err)
+	return k, int16(unzigzag(uv)), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. It
+// stops before a value that overflows int16. See Iterator.NextBatch.
+func (it *I16Iterator) NextBatch(out []int16) (n int) {
+	if it.err != nil {
+		return 0
+	}
+
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			if uv > math.MaxUint16 {
+				it.err = &OverflowError{Index: it.it.k - m + i}
+				return n + i
+			}
+			out[n+i] = int16(unzigzag(uv))
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. When the value is too
+// wide for int16, its low-order bytes are returned together with
+// ErrOverflow. See Iterator.Value.
+func (it *I16Iterator) Value(k int) (int16, error) {
+	uv, err := it.it.Value(k)
+	if err == nil && uv > math.MaxUint16 {
+		err = ErrOverflow
+	}
+	return int16(unzigzag(uv)), err
+}
+
+// Err returns an *OverflowError when the iteration stopped at a value that
+// overflows int16, or ErrConcurrentModification when the layout of the
+// dictionary changed.
+func (it *I16Iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *I16Iterator) Reset() {
+	it.it.Reset()
+	it.err = nil
+}
+
+// I32Iterator enables iteration over a dictionary of int32 values.
+type I32Iterator struct {
+	it  Iterator
+	err error
+}
+
+// IterI32 creates an iterator for a dictionary of int32 values.
+func (d *Dict) IterI32() I32Iterator {
+	return I32Iterator{it: d.Iter()}
+}
+
+// Next returns the next index and value from the dictionary. If there is not
+// a next value, or the next value overflows int32, the ok return value will
+// be false.
+func (it *I32Iterator) Next() (k int, v int32, ok bool) {
+	k, uv, ok := it.it.nextNarrow(math.MaxUint32, &it.err)
+	return k, int32(unzigzag(uv)), ok
+}
+
+// NextBatch decodes the next values into out and returns their number. It
+// stops before a value that overflows int32. See Iterator.NextBatch.
+func (it *I32Iterator) NextBatch(out []int32) (n int) {
+	if it.err != nil {
+		return 0
+	}
+
+	var buf [batchSize]uint64
+	for n < len(out) {
+		m := it.it.NextBatch(buf[:batchLen(len(out)-n)])
+		for i, uv := range buf[:m] {
+			if uv > math.MaxUint32 {
+				it.err = &OverflowError{Index: it.it.k - m + i}
+				return n + i
+			}
+			out[n+i] = int32(unzigzag(uv))
+		}
+		if n += m; m < batchSize {
+			break
+		}
+	}
+	return
+}
+
+// Value returns the k-th value from the dictionary. When the value is too
+// wide for int32, its low-order bytes are returned together with
+// ErrOverflow. See Iterator.Value.
+func (it *I32Iterator) Value(k int) (int32, error) {
+	uv, err := it.it.Value(k)
+	if err == nil && uv > math.MaxUint32 {
+		err = ErrOverflow
+	}
+	return int32(unzigzag(uv)), err
+}
+
+// Err returns an *OverflowError when the iteration stopped at a value that
+// overflows int32, or ErrConcurrentModification when the layout of the
+// dictionary changed.
+func (it *I32Iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.it.Err()
+}
+
+// Reset resets the iterator to the first element of the dictionary.
+func (it *I32Iterator) Reset() {
+	it.it.Reset()
+	it.err = nil
+}
+
+// batchSize is the number of values the typed iterators decode at once.
+const batchSize = 256
+
+// batchLen returns the number of values to decode for n requested values.
+func batchLen(n int) int {
+	if n < batchSize {
+		return n
+	}
+	return batchSize
+}
+
+// nextNarrow is like Next, but stops at a value larger than max, recording an
+// *OverflowError in err. Once err is set, no more values are returned.
+func (it *Iterator) nextNarrow(max uint64, err *error) (k int, v uint64, ok bool) {
+	if *err != nil {
+		return
+	}
+
+	if k, v, ok = it.Next(); ok && v > max {
+		*err = &OverflowError{Index: k}
+		return k, v, false
+	}
+	return
+}
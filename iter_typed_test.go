@@ -0,0 +1,241 @@
+package dac
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestTypedIterators(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	const n = 1_000
+	ints := make([]int64, n)
+	f32 := make([]float32, n)
+	f64 := make([]float64, n)
+	times := make([]time.Time, n)
+	bools := make([]bool, n)
+	for i := 0; i < n; i++ {
+		ints[i] = r.Int63() >> uint(r.Intn(63))
+		if r.Intn(2) == 1 {
+			ints[i] = -ints[i]
+		}
+		f32[i] = float32(r.NormFloat64())
+		f64[i] = r.NormFloat64()
+		times[i] = time.Unix(0, ints[i])
+		bools[i] = r.Intn(2) == 1
+	}
+	f64[3] = math.Inf(-1)
+
+	d, _ := New()
+	d.WriteI64List(ints)
+	d.Close()
+
+	itI := d.IterI64()
+	itT := d.IterDateTime()
+	for i := 0; ; i++ {
+		k, v, ok := itI.Next()
+		kt, vt, okt := itT.Next()
+		if !ok || !okt {
+			if i != n || ok || okt {
+				t.Fatalf("iteration stopped at %d\n", i)
+			}
+			break
+		}
+		if k != i || v != ints[i] || kt != i || !vt.Equal(times[i]) {
+			t.Fatalf("index %d - got: (%d, %d, %v), want: (%d, %v)\n", i, k, v, vt, ints[i], times[i])
+		}
+	}
+	if v, err := itI.Value(7); err != nil || v != ints[7] {
+		t.Errorf("I64Iterator.Value - got: %d, want: %d, err: %v\n", v, ints[7], err)
+	}
+
+	d.Reset()
+	d.WriteFloat32List(f32)
+	d.Close()
+	it32 := d.IterFloat32()
+	for k, v, ok := it32.Next(); ok; k, v, ok = it32.Next() {
+		if v != f32[k] {
+			t.Fatalf("Float32Iterator - index %d: got: %v, want: %v\n", k, v, f32[k])
+		}
+	}
+
+	d.Reset()
+	d.WriteFloat64List(f64)
+	d.Close()
+	it64 := d.IterFloat64()
+	for k, v, ok := it64.Next(); ok; k, v, ok = it64.Next() {
+		if v != f64[k] {
+			t.Fatalf("Float64Iterator - index %d: got: %v, want: %v\n", k, v, f64[k])
+		}
+	}
+
+	d.Reset()
+	d.WriteBoolList(bools)
+	d.Close()
+	itB := d.IterBool()
+	var count int
+	for k, v, ok := itB.Next(); ok; k, v, ok = itB.Next() {
+		if v != bools[k] {
+			t.Fatalf("BoolIterator - index %d: got: %v, want: %v\n", k, v, bools[k])
+		}
+		count++
+	}
+	itB.Reset()
+	if k, _, ok := itB.Next(); !ok || k != 0 || count != n {
+		t.Errorf("BoolIterator - count: %d, after Reset: (%d, %v)\n", count, k, ok)
+	}
+}
+
+func TestNarrowIterators(t *testing.T) {
+	d, _ := New()
+	d.WriteU64List([]uint64{1, 200, 255, 256, 3})
+	d.Close()
+
+	it := d.IterU8()
+	var got []uint8
+	for _, v, ok := it.Next(); ok; _, v, ok = it.Next() {
+		got = append(got, v)
+	}
+	if len(got) != 3 || got[2] != 255 {
+		t.Errorf("U8Iterator - got: %v\n", got)
+	}
+	var oe *OverflowError
+	if err := it.Err(); !errors.As(err, &oe) || oe.Index != 3 || !errors.Is(err, ErrOverflow) {
+		t.Errorf("U8Iterator.Err - got: %v\n", err)
+	}
+	if _, _, ok := it.Next(); ok {
+		t.Error("U8Iterator continued after an overflow")
+	}
+	if _, err := it.Value(3); !errors.Is(err, ErrOverflow) {
+		t.Errorf("U8Iterator.Value - got: %v, want: %v\n", err, ErrOverflow)
+	}
+
+	it.Reset()
+	if k, v, ok := it.Next(); !ok || k != 0 || v != 1 || it.Err() != nil {
+		t.Errorf("U8Iterator after Reset - got: (%d, %d, %v), err: %v\n", k, v, ok, it.Err())
+	}
+
+	it16 := d.IterU16()
+	var n16 int
+	for _, _, ok := it16.Next(); ok; _, _, ok = it16.Next() {
+		n16++
+	}
+	if n16 != 5 || it16.Err() != nil {
+		t.Errorf("U16Iterator - got %d values, err: %v\n", n16, it16.Err())
+	}
+
+	signed := []int32{-1, 5, math.MaxInt32, math.MinInt32, -70_000}
+	d.Reset()
+	for _, v := range signed {
+		d.WriteI32(v)
+	}
+	d.WriteI64(math.MaxInt32 + 1)
+	d.Close()
+
+	it32 := d.IterI32()
+	for i, v := range signed {
+		if k, got, ok := it32.Next(); !ok || k != i || got != v {
+			t.Errorf("I32Iterator - got: (%d, %d, %v), want: (%d, %d)\n", k, got, ok, i, v)
+		}
+	}
+	if _, _, ok := it32.Next(); ok || it32.Err() == nil {
+		t.Error("I32Iterator - expected an overflow")
+	}
+
+	it8, it16s, itU32 := d.IterI8(), d.IterI16(), d.IterU32()
+	if _, v, ok := it8.Next(); !ok || v != -1 {
+		t.Errorf("I8Iterator - got: (%d, %v)\n", v, ok)
+	}
+	if _, v, ok := it16s.Next(); !ok || v != -1 {
+		t.Errorf("I16Iterator - got: (%d, %v)\n", v, ok)
+	}
+	if _, v, ok := itU32.Next(); !ok || v != 1 {
+		t.Errorf("U32Iterator - got: (%d, %v)\n", v, ok)
+	}
+}
+
+func TestTypedNextBatch(t *testing.T) {
+	signed := []int64{-1, 2, -300, 1 << 40, math.MinInt64, 7}
+	d, _ := New()
+	d.WriteI64List(signed)
+	d.Close()
+
+	out := make([]int64, 4)
+	it := d.IterI64()
+	if m := it.NextBatch(out); m != 4 || out[2] != -300 || out[3] != 1<<40 {
+		t.Errorf("I64Iterator - got: %v\n", out[:m])
+	}
+	if m := it.NextBatch(out); m != 2 || out[0] != math.MinInt64 || out[1] != 7 {
+		t.Errorf("I64Iterator - got: %v\n", out[:m])
+	}
+
+	it16 := d.IterI16()
+	out16 := make([]int16, 10)
+	if m := it16.NextBatch(out16); m != 3 || out16[2] != -300 {
+		t.Errorf("I16Iterator - got: %v\n", out16[:m])
+	}
+	var oe *OverflowError
+	if err := it16.Err(); !errors.As(err, &oe) || oe.Index != 3 {
+		t.Errorf("I16Iterator.Err - got: %v\n", err)
+	}
+	if m := it16.NextBatch(out16); m != 0 {
+		t.Errorf("I16Iterator continued after an overflow with %d values\n", m)
+	}
+	it16.Reset()
+	if m := it16.NextBatch(out16[:2]); m != 2 || out16[0] != -1 || it16.Err() != nil {
+		t.Errorf("I16Iterator after Reset - got: %v, err: %v\n", out16[:m], it16.Err())
+	}
+
+	// overflow beyond the first internal batch
+	d.Reset()
+	for i := 0; i < 1_000; i++ {
+		d.WriteU64(uint64(i % 256))
+	}
+	d.WriteU64(256)
+	d.Close()
+
+	it8 := d.IterU8()
+	out8 := make([]uint8, 2_000)
+	if m := it8.NextBatch(out8); m != 1_000 || out8[999] != 999%256 {
+		t.Errorf("U8Iterator - got %d values\n", m)
+	}
+	if err := it8.Err(); !errors.As(err, &oe) || oe.Index != 1_000 {
+		t.Errorf("U8Iterator.Err - got: %v\n", err)
+	}
+
+	floats := []float64{1.5, math.Inf(-1), -0.25}
+	d.Reset()
+	for _, v := range floats {
+		d.WriteFloat64(v)
+	}
+	d.Close()
+
+	outF := make([]float64, 4)
+	itF := d.IterFloat64()
+	if m := itF.NextBatch(outF); m != 3 || outF[0] != 1.5 || outF[1] != math.Inf(-1) || outF[2] != -0.25 {
+		t.Errorf("Float64Iterator - got: %v\n", outF[:m])
+	}
+}
+
+func BenchmarkFloat64Iterator(b *testing.B) {
+	const n = 1_000
+
+	r := rand.New(rand.NewSource(15))
+	d, _ := New(n)
+	for i := 0; i < n; i++ {
+		d.WriteFloat64(r.NormFloat64())
+	}
+	d.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		it := d.IterFloat64()
+		for _, _, ok := it.Next(); ok; _, _, ok = it.Next() {
+		}
+	}
+}
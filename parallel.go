@@ -0,0 +1,177 @@
+package dac
+
+import (
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// FromParallel constructs a dictionary from the given values, using the
+// given number of goroutines. When workers is smaller than 1, GOMAXPROCS
+// goroutines are used. The resulting dictionary is identical to the one
+// constructed by From and is closed for writing.
+//
+// The values are split in blocks that are aligned to the rank blocks of
+// level 0. A first pass counts the number of entries every block contributes
+// to each level, so that all levels can be allocated at their exact size. A
+// second pass fills the blocks concurrently.
+func FromParallel(values []uint64, workers int) *Dict {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	size := (len(values)+workers-1)/workers + 511
+	size -= size & 511
+	if size == 0 {
+		size = 512
+	}
+	nBlocks := (len(values) + size - 1) / size
+	block := func(b int) []uint64 {
+		if hi := (b + 1) * size; hi < len(values) {
+			return values[b*size : hi]
+		}
+		return values[b*size:]
+	}
+
+	// count the entries of each block per level
+	offsets := make([][nStreams64]int, nBlocks+1)
+	parallel(nBlocks, func(b int) {
+		var hist [nStreams64]int
+		for _, v := range block(b) {
+			hist[maxByteIdx(v)]++
+		}
+
+		// a value of n bytes has an entry at levels 0 to n-1
+		for l, c := nStreams64-1, 0; 0 <= l; l-- {
+			c += hist[l]
+			offsets[b+1][l] = c
+		}
+	})
+
+	// turn the counts into offsets
+	for b := 1; b <= nBlocks; b++ {
+		for l := range offsets[b] {
+			offsets[b][l] += offsets[b-1][l]
+		}
+	}
+	total := offsets[nBlocks]
+
+	d := Dict{}
+	for l := range d.chunks {
+		d.chunks[l] = make([]byte, total[l])
+	}
+	for l := range d.bitArr {
+		d.bitArr[l] = make([]uint64, (total[l]+63)>>6)
+	}
+
+	// The first and the last word holding bits of a block at some level can
+	// be shared with the neighboring blocks. These words are collected per
+	// block and merged afterwards.
+	edges := make([][nStreams64 - 1][2]uint64, nBlocks)
+	parallel(nBlocks, func(b int) {
+		var first, last [nStreams64 - 1]int
+		for l := range first {
+			first[l] = offsets[b][l] >> 6
+			last[l] = (offsets[b+1][l] - 1) >> 6
+		}
+
+		pos := offsets[b]
+		for _, v := range block(b) {
+			l := 0
+			d.chunks[0][pos[0]] = uint8(v)
+
+			for v >>= 8; v != 0 && l < nStreams64-1; v >>= 8 {
+				switch k := pos[l]; k >> 6 {
+				case first[l]:
+					edges[b][l][0] |= 1 << (k & 63)
+				case last[l]:
+					edges[b][l][1] |= 1 << (k & 63)
+				default:
+					d.bitArr[l][k>>6] |= 1 << (k & 63)
+				}
+
+				pos[l]++
+				l++
+				d.chunks[l][pos[l]] = uint8(v)
+			}
+			pos[l]++
+		}
+	})
+
+	for b := range edges {
+		for l, e := range edges[b] {
+			if e[0] != 0 {
+				d.bitArr[l][offsets[b][l]>>6] |= e[0]
+			}
+			if e[1] != 0 {
+				d.bitArr[l][(offsets[b+1][l]-1)>>6] |= e[1]
+			}
+		}
+	}
+
+	for l := range d.ranks {
+		d.ranks[l] = buildRanksParallel(d.bitArr[l], workers)
+	}
+
+	return &d
+}
+
+// buildRanksParallel returns the ranks of the given bit array, using the
+// given number of goroutines.
+func buildRanksParallel(arr []uint64, workers int) []int {
+	ranks := make([]int, (len(arr)+7)>>3)
+	if len(ranks) == 0 {
+		return ranks
+	}
+
+	size := (len(ranks) + workers - 1) / workers
+	n := (len(ranks) + size - 1) / size
+	span := func(w int) (lo, hi int) {
+		if lo, hi = w*size, (w+1)*size; len(ranks) < hi {
+			hi = len(ranks)
+		}
+		return
+	}
+
+	// local prefix counts per group of blocks
+	base := make([]int, n+1)
+	parallel(n, func(w int) {
+		lo, hi := span(w)
+
+		var prefix int
+		for j := lo << 3; j < hi<<3 && j < len(arr); j++ {
+			if j&7 == 0 {
+				ranks[j>>3] = prefix
+			}
+			prefix += bits.OnesCount64(arr[j])
+		}
+		base[w+1] = prefix
+	})
+
+	for w := 1; w <= n; w++ {
+		base[w] += base[w-1]
+	}
+
+	parallel(n, func(w int) {
+		lo, hi := span(w)
+		for b := lo; b < hi; b++ {
+			ranks[b] += base[w]
+		}
+	})
+
+	return ranks
+}
+
+// parallel calls f(i) for every i in [0, n), each in its own goroutine, and
+// waits for all calls to return.
+func parallel(n int, f func(i int)) {
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
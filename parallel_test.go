@@ -0,0 +1,59 @@
+package dac
+
+import "testing"
+
+// equalDict reports whether the levels of a and b hold the same contents.
+func equalDict(a, b *Dict) bool {
+	for l := range a.chunks {
+		if string(a.chunks[l]) != string(b.chunks[l]) {
+			return false
+		}
+	}
+
+	for l := range a.bitArr {
+		if len(a.bitArr[l]) != len(b.bitArr[l]) || len(a.ranks[l]) != len(b.ranks[l]) {
+			return false
+		}
+		for i := range a.bitArr[l] {
+			if a.bitArr[l][i] != b.bitArr[l][i] {
+				return false
+			}
+		}
+		for i := range a.ranks[l] {
+			if a.ranks[l][i] != b.ranks[l][i] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func TestFromParallel(t *testing.T) {
+	for _, n := range []int{0, 1, 511, 512, 513, 10_000, 100_003} {
+		numbers := zipfNumbers(n, 15)
+		want := From(numbers)
+
+		for _, workers := range []int{0, 1, 2, 3, 8, 64} {
+			got := FromParallel(numbers, workers)
+			if !equalDict(got, want) {
+				t.Errorf("n: %d, workers: %d - dictionaries differ\n", n, workers)
+			}
+		}
+	}
+
+	checkDict(t, FromParallel(zipfNumbers(5_000, 16), 4), zipfNumbers(5_000, 16))
+}
+
+func BenchmarkFromParallel(b *testing.B) {
+	const n = 1_000_000
+
+	numbers := zipfNumbers(n, 15)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		FromParallel(numbers, 0)
+	}
+}
@@ -0,0 +1,126 @@
+package dac
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// ReadU64Range returns the uint64 values in the index range [lo, hi). One
+// can avoid the allocation of the return slice by supplying a slice of a
+// size sufficient to store the values. Supplying a slice is optional. The
+// decoding starts at lo, so the cost is proportional to hi-lo rather than
+// to the length of the dictionary. The dictionary must be closed.
+func (d *Dict) ReadU64Range(lo, hi int, values []uint64) ([]uint64, error) {
+	err := d.readRange(lo, hi, func(n int) {
+		if len(values) < n {
+			values = make([]uint64, n)
+		} else {
+			values = values[:n]
+		}
+	}, func(j int, uv uint64) {
+		values[j] = uv
+	})
+	return values, err
+}
+
+// ReadI64Range returns the int64 values in the index range [lo, hi). See
+// ReadU64Range.
+func (d *Dict) ReadI64Range(lo, hi int, values []int64) ([]int64, error) {
+	err := d.readRange(lo, hi, func(n int) {
+		if len(values) < n {
+			values = make([]int64, n)
+		} else {
+			values = values[:n]
+		}
+	}, func(j int, uv uint64) {
+		values[j] = unzigzag(uv)
+	})
+	return values, err
+}
+
+// ReadFloat32Range returns the float32 values in the index range [lo, hi).
+// See ReadU64Range.
+func (d *Dict) ReadFloat32Range(lo, hi int, values []float32) ([]float32, error) {
+	err := d.readRange(lo, hi, func(n int) {
+		if len(values) < n {
+			values = make([]float32, n)
+		} else {
+			values = values[:n]
+		}
+	}, func(j int, uv uint64) {
+		values[j] = math.Float32frombits(bits.ReverseBytes32(uint32(uv)))
+	})
+	return values, err
+}
+
+// ReadFloat64Range returns the float64 values in the index range [lo, hi).
+// See ReadU64Range.
+func (d *Dict) ReadFloat64Range(lo, hi int, values []float64) ([]float64, error) {
+	err := d.readRange(lo, hi, func(n int) {
+		if len(values) < n {
+			values = make([]float64, n)
+		} else {
+			values = values[:n]
+		}
+	}, func(j int, uv uint64) {
+		values[j] = unreverse64(uv)
+	})
+	return values, err
+}
+
+// ReadDateTimeRange returns the time.Time values in the index range
+// [lo, hi). No timezone is read. See ReadU64Range.
+func (d *Dict) ReadDateTimeRange(lo, hi int, dateTimes []time.Time) ([]time.Time, error) {
+	err := d.readRange(lo, hi, func(n int) {
+		if len(dateTimes) < n {
+			dateTimes = make([]time.Time, n)
+		} else {
+			dateTimes = dateTimes[:n]
+		}
+	}, func(j int, uv uint64) {
+		dateTimes[j] = time.Unix(0, unzigzag(uv))
+	})
+	return dateTimes, err
+}
+
+// ReadBoolRange returns the boolean values in the index range [lo, hi). See
+// ReadU64Range.
+func (d *Dict) ReadBoolRange(lo, hi int, values []bool) ([]bool, error) {
+	if err := checkRange(lo, hi, len(d.chunks[0])); err != nil {
+		return values[:0], err
+	}
+
+	if len(values) < hi-lo {
+		values = make([]bool, hi-lo)
+	} else {
+		values = values[:hi-lo]
+	}
+
+	// booleans are a single byte
+	for i, c := range d.chunks[0][lo:hi] {
+		values[i] = c != 0
+	}
+	return values, nil
+}
+
+// readRange calls size with the length of the index range [lo, hi), after
+// validating the range, and then f with the offset in the range and the value
+// of every entry. On an invalid range, size is called with 0.
+func (d *Dict) readRange(lo, hi int, size func(n int), f func(j int, v uint64)) error {
+	if err := checkRange(lo, hi, len(d.chunks[0])); err != nil {
+		size(0)
+		return err
+	}
+
+	size(hi - lo)
+	if lo == hi {
+		return nil
+	}
+
+	ranks := d.ranksAt(lo)
+	for k := lo; k < hi; k++ {
+		f(k-lo, d.decode(k, &ranks))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package dac
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+var readRanges = [][2]int{{0, 0}, {0, 1}, {0, 3_000}, {17, 17}, {63, 700}, {511, 1_537}, {2_999, 3_000}}
+
+func TestReadU64Range(t *testing.T) {
+	numbers := zipfNumbers(3_000, 15)
+	d := From(numbers)
+
+	buf := make([]uint64, 10)
+	for _, r := range readRanges {
+		got, err := d.ReadU64Range(r[0], r[1], buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := numbers[r[0]:r[1]]
+		if len(got) != len(want) {
+			t.Fatalf("ReadU64Range%v - got %d values, want: %d\n", r, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("ReadU64Range%v - index %d: got: %d, want: %d\n", r, r[0]+i, got[i], want[i])
+			}
+		}
+	}
+
+	for _, r := range [][2]int{{-1, 2}, {5, 4}, {0, 3_001}} {
+		if _, err := d.ReadU64Range(r[0], r[1], nil); err == nil {
+			t.Errorf("ReadU64Range%v - expected error\n", r)
+		}
+	}
+}
+
+func TestReadTypedRange(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	const n = 3_000
+	ints := make([]int64, n)
+	f32 := make([]float32, n)
+	f64 := make([]float64, n)
+	bools := make([]bool, n)
+	for i := 0; i < n; i++ {
+		ints[i] = r.Int63() >> uint(r.Intn(63))
+		if r.Intn(2) == 1 {
+			ints[i] = -ints[i]
+		}
+		f32[i] = float32(r.NormFloat64())
+		f64[i] = r.NormFloat64()
+		bools[i] = r.Intn(2) == 1
+	}
+	f64[100] = math.Inf(1)
+
+	di, _ := New()
+	di.WriteI64List(ints)
+	di.Close()
+	d32, _ := New()
+	d32.WriteFloat32List(f32)
+	d32.Close()
+	d64, _ := New()
+	d64.WriteFloat64List(f64)
+	d64.Close()
+	db, _ := New()
+	db.WriteBoolList(bools)
+	db.Close()
+
+	for _, rg := range readRanges {
+		lo, hi := rg[0], rg[1]
+
+		gi, err := di.ReadI64Range(lo, hi, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gt, err := di.ReadDateTimeRange(lo, hi, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g32, err := d32.ReadFloat32Range(lo, hi, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g64, err := d64.ReadFloat64Range(lo, hi, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gb, err := db.ReadBoolRange(lo, hi, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := lo; i < hi; i++ {
+			j := i - lo
+			if gi[j] != ints[i] || !gt[j].Equal(time.Unix(0, ints[i])) || g32[j] != f32[i] || g64[j] != f64[i] || gb[j] != bools[i] {
+				t.Fatalf("range %v - index %d: got: (%d, %v, %v, %v, %v)\n", rg, i, gi[j], gt[j], g32[j], g64[j], gb[j])
+			}
+		}
+	}
+}
+
+func BenchmarkReadU64Range(b *testing.B) {
+	const n, page = 1_000_000, 100
+
+	d := From(zipfNumbers(n, 15))
+	values := make([]uint64, page)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.ReadU64Range(n-page, n, values)
+	}
+}
@@ -0,0 +1,261 @@
+package dac
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// naiveArg returns the index of the first smallest, or when max is set,
+// the first largest value in numbers[lo:hi].
+func naiveArg(numbers []uint64, lo, hi int, max bool) int {
+	idx := lo
+	for k := lo + 1; k < hi; k++ {
+		if (!max && numbers[k] < numbers[idx]) || (max && numbers[k] > numbers[idx]) {
+			idx = k
+		}
+	}
+	return idx
+}
+
+func checkRMQ(t *testing.T, d *Dict, numbers []uint64, r *rand.Rand) {
+	t.Helper()
+
+	n := len(numbers)
+	for i := 0; i < 2_000; i++ {
+		lo := r.Intn(n)
+		hi := lo + 1 + r.Intn(n-lo)
+		if i&3 == 0 {
+			hi = lo + 1 + r.Intn(3*rmqBlock)
+			if n < hi {
+				hi = n
+			}
+		}
+
+		if got, err := d.ArgMin(lo, hi); err != nil || got != naiveArg(numbers, lo, hi, false) {
+			t.Fatalf("ArgMin(%d, %d) - got: %d, want: %d, err: %v\n", lo, hi, got, naiveArg(numbers, lo, hi, false), err)
+		}
+		if got, err := d.ArgMax(lo, hi); err != nil || got != naiveArg(numbers, lo, hi, true) {
+			t.Fatalf("ArgMax(%d, %d) - got: %d, want: %d, err: %v\n", lo, hi, got, naiveArg(numbers, lo, hi, true), err)
+		}
+	}
+}
+
+func TestArgMinArgMax(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	for _, n := range []int{1, 63, 64, 65, 1_000, 10_000} {
+		numbers := zipfNumbers(n, 15)
+
+		d, _ := New()
+		d.EnableRMQ()
+		d.WriteU64List(numbers)
+		d.Close()
+
+		checkRMQ(t, d, numbers, r)
+
+		if _, err := d.ArgMin(0, 0); err == nil {
+			t.Error("expected error for empty range")
+		}
+		if _, err := d.ArgMax(0, n+1); err == nil {
+			t.Error("expected error for range beyond length")
+		}
+	}
+}
+
+func TestArgMinTies(t *testing.T) {
+	numbers := make([]uint64, 1_000)
+	for i := range numbers {
+		numbers[i] = uint64(i % 3)
+	}
+
+	d, _ := New()
+	d.EnableRMQ()
+	d.WriteU64List(numbers)
+	d.Close()
+
+	checkRMQ(t, d, numbers, rand.New(rand.NewSource(15)))
+}
+
+func TestRMQState(t *testing.T) {
+	numbers := zipfNumbers(100, 15)
+	d := From(numbers)
+	if _, err := d.ArgMin(0, 100); err == nil {
+		t.Error("expected error when the RMQ index is not enabled")
+	}
+
+	d.EnableRMQ()
+	if _, err := d.ArgMin(0, 100); err == nil {
+		t.Error("expected error before Close")
+	}
+	d.Close()
+	if _, err := d.ArgMin(0, 100); err != nil {
+		t.Error(err)
+	}
+
+	d.WriteU64(1 << 63)
+	numbers = append(numbers, 1<<63)
+	if _, err := d.ArgMax(0, 100); err == nil {
+		t.Error("expected error after a write")
+	}
+	d.Close()
+	if k, err := d.ArgMax(0, 101); err != nil || k != 100 {
+		t.Errorf("ArgMax - got: %d, want: 100, err: %v\n", k, err)
+	}
+
+	c := d.Clone()
+	checkRMQ(t, c, numbers, rand.New(rand.NewSource(15)))
+
+	d.DisableRMQ()
+	if _, err := d.ArgMin(0, 100); err == nil {
+		t.Error("expected error after DisableRMQ")
+	}
+}
+
+func TestRMQSameLengthUpdates(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(d *Dict)
+	}{
+		{"UpdateU64At", func(d *Dict) { d.UpdateU64At(500, 1) }},
+		{"UpdateU64At same length", func(d *Dict) { d.UpdateU64At(500, 1001) }},
+		{"UpdateU64Range", func(d *Dict) { d.UpdateU64Range(500, []uint64{1, 2}) }},
+		{"InsertU64At and RemoveAt", func(d *Dict) { d.InsertU64At(500, 1); d.RemoveAt(999) }},
+		{"RemoveIndexes and WriteU64", func(d *Dict) { d.RemoveIndexes([]int{3}); d.WriteU64(1) }},
+		{"Reset and rewrite", func(d *Dict) {
+			d.Reset()
+			for i := 0; i < 1_000; i++ {
+				d.WriteU64(uint64(2_000 - i))
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		d, _ := New()
+		d.EnableRMQ()
+		for i := 0; i < 1_000; i++ {
+			d.WriteU64(1_000)
+		}
+		d.Close()
+
+		c := d.Clone()
+		tt.modify(d)
+
+		if _, err := d.ArgMin(0, 1_000); err == nil {
+			t.Errorf("%s - expected error for a stale index\n", tt.name)
+		}
+		var buf bytes.Buffer
+		d.WriteTo(&buf)
+		if flags := buf.Bytes()[6]; flags&flagRMQ != 0 {
+			t.Errorf("%s - WriteTo wrote a stale index\n", tt.name)
+		}
+
+		d.Close()
+		checkRMQ(t, d, d.ReadU64List(nil), rand.New(rand.NewSource(15)))
+
+		// the clone keeps its own, up to date index
+		if k, err := c.ArgMin(0, 1_000); err != nil || k != 0 {
+			t.Errorf("%s - clone ArgMin - got: %d, err: %v\n", tt.name, k, err)
+		}
+	}
+}
+
+func TestRMQSerialization(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 5_000} {
+		numbers := zipfNumbers(n, 15)
+
+		d, _ := New()
+		d.EnableRMQ()
+		d.WriteU64List(numbers)
+		d.Close()
+
+		var buf bytes.Buffer
+		m, err := d.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != int64(buf.Len()) || m&7 != 0 {
+			t.Errorf("n: %d - WriteTo returned %d, wrote %d bytes\n", n, m, buf.Len())
+		}
+		data := buf.Bytes()
+
+		var got Dict
+		if m, err = got.ReadFrom(bytes.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+		if m != int64(len(data)) {
+			t.Errorf("n: %d - ReadFrom returned %d, want: %d\n", n, m, len(data))
+		}
+		checkDict(t, &got, numbers)
+		if n != 0 {
+			checkRMQ(t, &got, numbers, rand.New(rand.NewSource(15)))
+		}
+
+		// the levels are unaffected by the RMQ index
+		dd, err := OpenDiskDict(bytes.NewReader(data), 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dd.Len() != n {
+			t.Errorf("n: %d - DiskDict length: %d\n", n, dd.Len())
+		}
+	}
+}
+
+func TestRMQCorrupt(t *testing.T) {
+	d, _ := New()
+	d.EnableRMQ()
+	d.WriteU64List(zipfNumbers(100, 15))
+	d.Close()
+
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	data := buf.Bytes()
+
+	// offset of the smallest value of the last block beyond the entries
+	bad := append([]byte(nil), data...)
+	bad[len(bad)-16+1] = 40
+	if _, err := new(Dict).ReadFrom(bytes.NewReader(bad)); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("invalid offset - got: %v, want: %v\n", err, ErrCorrupt)
+	}
+
+	if _, err := new(Dict).ReadFrom(bytes.NewReader(data[:len(data)-1])); err == nil {
+		t.Error("expected error for truncated RMQ index")
+	}
+
+	bad = append([]byte(nil), data...)
+	bad[6] = 2
+	if _, err := new(Dict).ReadFrom(bytes.NewReader(bad)); err == nil {
+		t.Error("expected error for unsupported flags")
+	}
+}
+
+func BenchmarkArgMin(b *testing.B) {
+	const n = 100_000
+
+	d, _ := New()
+	d.EnableRMQ()
+	d.WriteU64List(zipfNumbers(n, 15))
+	d.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.ArgMin(17, n-17)
+	}
+}
+
+func BenchmarkMinU64(b *testing.B) {
+	const n = 100_000
+
+	d := From(zipfNumbers(n, 15))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.MinU64(17, n-17)
+	}
+}
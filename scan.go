@@ -0,0 +1,185 @@
+package dac
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"time"
+	"unsafe"
+)
+
+// The typed Scan and Search functions encode the searched value exactly like
+// the corresponding Write function. Floats are therefore matched on their
+// bits: -0 and +0 are different values and a NaN only matches a NaN with the
+// same bits. The Search functions require the encoded values to be sorted,
+// see Search.
+
+// ScanBool returns the index of the first instance of v, or -1 when v is not
+// found.
+func (d *Dict) ScanBool(v bool) int {
+	return d.Scan(encodeBool(v))
+}
+
+// ScanI64 returns the index of the first instance of v, or -1 when v is not
+// found.
+func (d *Dict) ScanI64(v int64) int {
+	return d.Scan(zigzag(v))
+}
+
+// ScanFloat32 returns the index of the first instance of v, or -1 when v is
+// not found.
+func (d *Dict) ScanFloat32(v float32) int {
+	return d.Scan(reverse32(v))
+}
+
+// ScanFloat64 returns the index of the first instance of v, or -1 when v is
+// not found.
+func (d *Dict) ScanFloat64(v float64) int {
+	return d.Scan(reverse64(v))
+}
+
+// ScanDateTime returns the index of the first instance of t, or -1 when t is
+// not found. Times are compared with nanosecond precision, regardless of
+// their timezone.
+func (d *Dict) ScanDateTime(t time.Time) int {
+	return d.Scan(zigzag(t.UnixNano()))
+}
+
+// SearchBool returns the index of the first instance of v and the number of
+// instances. If v is not found, -1 is returned.
+func (d *Dict) SearchBool(v bool) (idx, l int) {
+	return d.Search(encodeBool(v))
+}
+
+// SearchI64 returns the index of the first instance of v and the number of
+// instances. If v is not found, -1 is returned.
+func (d *Dict) SearchI64(v int64) (idx, l int) {
+	return d.Search(zigzag(v))
+}
+
+// SearchFloat32 returns the index of the first instance of v and the number
+// of instances. If v is not found, -1 is returned.
+func (d *Dict) SearchFloat32(v float32) (idx, l int) {
+	return d.Search(reverse32(v))
+}
+
+// SearchFloat64 returns the index of the first instance of v and the number
+// of instances. If v is not found, -1 is returned.
+func (d *Dict) SearchFloat64(v float64) (idx, l int) {
+	return d.Search(reverse64(v))
+}
+
+// SearchDateTime returns the index of the first instance of t and the number
+// of instances. If t is not found, -1 is returned.
+func (d *Dict) SearchDateTime(t time.Time) (idx, l int) {
+	return d.Search(zigzag(t.UnixNano()))
+}
+
+// ScanEach calls f with the index of every instance of value, in ascending
+// order, until f returns false. See Scan.
+func (d *Dict) ScanEach(value uint64, f func(idx int) bool) {
+	d.scan(value, f)
+}
+
+// ScanAll returns the indexes of all instances of value, in ascending order.
+// It returns nil when value is not found.
+func (d *Dict) ScanAll(value uint64) (idx []int) {
+	d.ScanEach(value, func(i int) bool {
+		idx = append(idx, i)
+		return true
+	})
+	return
+}
+
+// CountValue returns the number of instances of value.
+func (d *Dict) CountValue(value uint64) (count int) {
+	d.ScanEach(value, func(int) bool {
+		count++
+		return true
+	})
+	return
+}
+
+// scan calls f with the index of every instance of value, in ascending
+// order, until f returns false. The candidates are the entries of level n,
+// the highest level of value, that hold its highest byte and do not continue
+// to a next level. Their indexes at the lower levels follow from select on
+// the presence bits. As select is monotonic, the matches are found in
+// ascending order.
+func (d *Dict) scan(value uint64, f func(idx int) bool) {
+	n := uint(maxByteIdx(value))
+	buf := (*[nStreams64]byte)(unsafe.Pointer(&value))
+
+	eachByte(d.chunks[n], buf[n], func(k int) bool {
+		if n < nStreams64-1 && d.bit(n, k) {
+			return true
+		}
+
+		for l := n; 0 < l; l-- {
+			if k = d.selectBit(l-1, k); d.chunks[l-1][k] != buf[l-1] {
+				return true
+			}
+		}
+		return f(k)
+	})
+}
+
+// eachByte calls f with the index of every instance of c in arr, in
+// ascending order, until f returns false. Eight bytes are compared at once.
+func eachByte(arr []byte, c byte, f func(i int) bool) {
+	const lo7 = 0x7f7f7f7f7f7f7f7f
+	pattern := uint64(c) * 0x0101010101010101
+
+	i := 0
+	for ; i+8 <= len(arr); i += 8 {
+		// the high bit of a byte of m is set when the byte of x is zero
+		x := binary.LittleEndian.Uint64(arr[i:]) ^ pattern
+		m := ^((x&lo7 + lo7) | x | lo7)
+
+		for m != 0 {
+			if !f(i + bits.TrailingZeros64(m)>>3) {
+				return
+			}
+			m &= m - 1
+		}
+	}
+
+	for ; i < len(arr); i++ {
+		if arr[i] == c && !f(i) {
+			return
+		}
+	}
+}
+
+// encodeBool encodes a bool value like WriteBool.
+func encodeBool(v bool) uint64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// zigzag encodes an int64 value like WriteI64.
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+// reverse32 encodes a float32 value like WriteFloat32.
+func reverse32(v float32) uint64 {
+	return uint64(bits.ReverseBytes32(math.Float32bits(v)))
+}
+
+// reverse64 encodes a float64 value like WriteFloat64.
+func reverse64(v float64) uint64 {
+	return bits.ReverseBytes64(math.Float64bits(v))
+}
+
+// unzigzag decodes a zigzag encoded int64 value.
+func unzigzag(uv uint64) int64 {
+	return int64((uv >> 1) ^ -(uv & 1))
+}
+
+// unreverse64 decodes a float64 value stored with its bytes reversed.
+func unreverse64(uv uint64) float64 {
+	return math.Float64frombits(bits.ReverseBytes64(uv))
+}
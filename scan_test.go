@@ -0,0 +1,364 @@
+package dac
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestScanI64(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	numbers := make([]int64, 1_000)
+	for i := range numbers {
+		numbers[i] = r.Int63n(2_000) - 1_000
+	}
+
+	d, _ := New()
+	d.WriteI64List(numbers)
+	d.Close()
+
+	for v := int64(-1_100); v < 1_100; v++ {
+		want := -1
+		for i, w := range numbers {
+			if w == v {
+				want = i
+				break
+			}
+		}
+		if got := d.ScanI64(v); got != want {
+			t.Fatalf("ScanI64(%d) - got: %d, want: %d\n", v, got, want)
+		}
+	}
+}
+
+func TestScanFloat(t *testing.T) {
+	nan := math.Float64frombits(0x7ff8000000000002)
+	numbers := []float64{1.5, math.Copysign(0, -1), -2.25, math.Inf(1), nan, 0, 1e300}
+
+	d, _ := New()
+	d.WriteFloat64List(numbers)
+	d.Close()
+
+	tests := []struct {
+		v    float64
+		want int
+	}{
+		{1.5, 0},
+		{math.Copysign(0, -1), 1},
+		{0, 5},
+		{-2.25, 2},
+		{math.Inf(1), 3},
+		{nan, 4},
+		{math.NaN(), -1}, // different bits
+		{1e300, 6},
+		{3, -1},
+	}
+
+	for _, tt := range tests {
+		if got := d.ScanFloat64(tt.v); got != tt.want {
+			t.Errorf("ScanFloat64(%v) - got: %d, want: %d\n", tt.v, got, tt.want)
+		}
+	}
+
+	f32 := []float32{0.5, float32(math.Copysign(0, -1)), 0, -7}
+	d.Reset()
+	d.WriteFloat32List(f32)
+	d.Close()
+
+	for i, v := range f32 {
+		if got := d.ScanFloat32(v); got != i {
+			t.Errorf("ScanFloat32(%v) - got: %d, want: %d\n", v, got, i)
+		}
+	}
+	if got := d.ScanFloat32(1); got != -1 {
+		t.Errorf("ScanFloat32(1) - got: %d, want: -1\n", got)
+	}
+}
+
+func TestScanDateTimeBool(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d, _ := New()
+	for i := 0; i < 100; i++ {
+		d.WriteDateTime(base.Add(time.Duration(i-50) * time.Second))
+	}
+	d.Close()
+
+	if got := d.ScanDateTime(base.Add(-10 * time.Second).In(time.Local)); got != 40 {
+		t.Errorf("ScanDateTime - got: %d, want: 40\n", got)
+	}
+	if got := d.ScanDateTime(base.Add(time.Hour)); got != -1 {
+		t.Errorf("ScanDateTime - got: %d, want: -1\n", got)
+	}
+
+	d.Reset()
+	d.WriteBoolList([]bool{false, false, true, false})
+	d.Close()
+
+	if got := d.ScanBool(true); got != 2 {
+		t.Errorf("ScanBool(true) - got: %d, want: 2\n", got)
+	}
+	if got := d.ScanBool(false); got != 0 {
+		t.Errorf("ScanBool(false) - got: %d, want: 0\n", got)
+	}
+}
+
+func TestSearchTyped(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	// the values are sorted on their encoding
+	ints := make([]int64, 1_000)
+	for i := range ints {
+		ints[i] = r.Int63n(200) - 100
+	}
+	sort.Slice(ints, func(i, j int) bool { return zigzag(ints[i]) < zigzag(ints[j]) })
+
+	d, _ := New()
+	d.WriteI64List(ints)
+	d.Close()
+
+	for v := int64(-110); v < 110; v++ {
+		want, count := -1, 0
+		for i, w := range ints {
+			if w == v {
+				if want == -1 {
+					want = i
+				}
+				count++
+			}
+		}
+
+		idx, l := d.SearchI64(v)
+		if idx != want || (want != -1 && l != count) {
+			t.Fatalf("SearchI64(%d) - got: (%d, %d), want: (%d, %d)\n", v, idx, l, want, count)
+		}
+	}
+
+	floats := []float64{0.5, math.Copysign(0, -1), 0, 2, 2, -1}
+	sort.Slice(floats, func(i, j int) bool { return reverse64(floats[i]) < reverse64(floats[j]) })
+
+	d.Reset()
+	d.WriteFloat64List(floats)
+	d.Close()
+
+	for _, v := range []float64{0.5, math.Copysign(0, -1), 0, 2, -1} {
+		idx, l := d.SearchFloat64(v)
+		if idx == -1 || floats[idx] != v || math.Signbit(floats[idx]) != math.Signbit(v) {
+			t.Errorf("SearchFloat64(%v) - got: (%d, %d)\n", v, idx, l)
+		}
+		if want := map[bool]int{true: 2, false: 1}[v == 2]; l != want {
+			t.Errorf("SearchFloat64(%v) - count: %d, want: %d\n", v, l, want)
+		}
+	}
+}
+
+func BenchmarkScanFloat64(b *testing.B) {
+	const n = 1_000
+
+	r := rand.New(rand.NewSource(15))
+	numbers := make([]float64, n)
+	for i := range numbers {
+		numbers[i] = r.NormFloat64()
+	}
+
+	d, _ := New()
+	d.WriteFloat64List(numbers)
+	d.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.ScanFloat64(numbers[i%n])
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	numbers := zipfNumbers(5_000, 15)
+	for i := range numbers {
+		numbers[i] %= 300 // values of 1 and 2 bytes with equal first bytes
+	}
+	d := From(numbers)
+
+	for v := uint64(0); v < 310; v++ {
+		var want []int
+		for i, w := range numbers {
+			if w == v {
+				want = append(want, i)
+			}
+		}
+
+		got := d.ScanAll(v)
+		if len(got) != len(want) {
+			t.Fatalf("ScanAll(%d) - got %d indexes, want: %d\n", v, len(got), len(want))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("ScanAll(%d) - index %d: got: %d, want: %d\n", v, i, got[i], want[i])
+			}
+		}
+
+		if got := d.CountValue(v); got != len(want) {
+			t.Fatalf("CountValue(%d) - got: %d, want: %d\n", v, got, len(want))
+		}
+	}
+
+	// stop after the second instance
+	var got []int
+	d.ScanEach(numbers[0], func(idx int) bool {
+		got = append(got, idx)
+		return len(got) < 2
+	})
+	if want := d.ScanAll(numbers[0]); len(want) >= 2 && (len(got) != 2 || got[1] != want[1]) {
+		t.Errorf("ScanEach - got: %v, want first two of: %v\n", got, want)
+	}
+}
+
+func BenchmarkCountValue(b *testing.B) {
+	const n = 1_000
+
+	numbers := zipfNumbers(n, 15)
+	d := From(numbers)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.CountValue(numbers[i%n])
+	}
+}
+
+// scanBytewise is the original implementation of Scan, which compares every
+// byte of level 0 and checks the higher levels of the candidates with rank.
+func scanBytewise(d *Dict, value uint64) int {
+	buf := (*[nStreams64]byte)(unsafe.Pointer(&value))
+	n := maxByteIdx(value)
+
+	search := buf[0]
+	for i, v := range d.chunks[0] {
+		if v == search {
+			// search and v have length 1
+			if n == 0 && !d.bit(0, i) {
+				return i
+			}
+
+			// search and value are longer than 1 byte
+			k, l := i, uint(0)
+			for l < uint(n) && d.bit(l, k) {
+				k = d.rank(l, k)
+				l++
+				// not equal
+				if buf[l] != d.chunks[l][k] {
+					break
+				}
+				// equal and same length
+				if l == uint(n) && ((l < nStreams64-1 && !d.bit(l, k)) || (l == nStreams64-1)) {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+func TestScanLevels(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	numbers := make([]uint64, 20_000)
+	for i := range numbers {
+		numbers[i] = r.Uint64() >> uint(8*r.Intn(8)) & 0x0101_0101_0101_0303
+	}
+	d := From(numbers)
+
+	for i := 0; i < 2_000; i++ {
+		v := numbers[r.Intn(len(numbers))]
+		if i&1 == 0 {
+			v = r.Uint64() >> uint(8*r.Intn(8)) & 0x0101_0101_0101_0303
+		}
+
+		want := scanBytewise(d, v)
+		if got := d.Scan(v); got != want {
+			t.Fatalf("Scan(%#x) - got: %d, want: %d\n", v, got, want)
+		}
+		if got := d.ScanAll(v); (want == -1) != (got == nil) || (got != nil && got[0] != want) {
+			t.Fatalf("ScanAll(%#x) - first: %v, want: %d\n", v, got, want)
+		}
+	}
+}
+
+func TestSelectBit(t *testing.T) {
+	d := From(zipfNumbers(10_000, 15))
+
+	for l := uint(0); l < nStreams64-1; l++ {
+		for j := 0; j < len(d.chunks[l+1]); j++ {
+			k := d.selectBit(l, j)
+			if !d.bit(l, k) || d.rank(l, k) != j {
+				t.Fatalf("level %d - selectBit(%d) = %d is not the %d-th set bit\n", l, j, k, j)
+			}
+		}
+	}
+}
+
+func TestEachByte(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	arr := make([]byte, 1_001)
+	for i := range arr {
+		arr[i] = byte(r.Intn(4)) * 0x7f // 0x00, 0x7f, 0xfe and 0x7d
+	}
+
+	for _, c := range []byte{0x00, 0x7f, 0xfe, 0x7d, 0x80, 0x01} {
+		var got []int
+		eachByte(arr, c, func(i int) bool {
+			got = append(got, i)
+			return true
+		})
+
+		var want []int
+		for i, b := range arr {
+			if b == c {
+				want = append(want, i)
+			}
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("eachByte(%#x) - got %d matches, want: %d\n", c, len(got), len(want))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("eachByte(%#x) - match %d: got: %d, want: %d\n", c, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+// The benchmarks on a large dictionary search a missing value of 3 bytes, so
+// that the whole dictionary is scanned.
+
+func BenchmarkScanLarge(b *testing.B) { // 80000 ns/op    0 B/op    0 allocs/op
+	d := From(zipfNumbers(1_000_000, 15))
+	const v = 0xfefefe
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.Scan(v)
+	}
+}
+
+func BenchmarkScanBytewiseLarge(b *testing.B) { // 800000 ns/op    0 B/op    0 allocs/op
+	d := From(zipfNumbers(1_000_000, 15))
+	const v = 0xfefefe
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		scanBytewise(d, v)
+	}
+}
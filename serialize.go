@@ -0,0 +1,388 @@
+package dac
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// The serialized format of a dictionary starts with a header, holding a
+// magic string, a format version, a flags field and the number of entries of
+// every level. The levels follow in order. Each level consists of its bytes,
+// zero-padded to a multiple of 8 bytes, followed by its presence bits and its
+// ranks. The bits and ranks are absent for the last level. When flagRMQ is
+// set, the block summaries of the RMQ index follow the levels: the number of
+// entries, the smallest and the largest value per block, and the offsets of
+// these values in their blocks, zero-padded to a multiple of 8 bytes. All
+// integers are stored as little-endian 64-bit words, except for the version
+// and the flags, which are 16-bit.
+const (
+	magic      = "DACS"
+	version    = 1
+	headerSize = 8 + 8*nStreams64
+)
+
+// flagRMQ marks the presence of the RMQ index.
+const flagRMQ = 1
+
+// maxEntries is the largest number of entries of a serialized dictionary, so
+// that its size fits in an int64.
+const maxEntries = 1 << 56
+
+// readStep is the largest number of bytes allocated at once while reading a
+// level, so that a corrupt header cannot allocate more than the input holds.
+const readStep = 1 << 20
+
+// layout describes where the sections of a serialized dictionary are found.
+type layout struct {
+	lens   [nStreams64]int   // number of entries per level
+	chunks [nStreams64]int64 // offset of the bytes of each level
+	bitArr [nStreams64]int64 // offset of the presence bits of each level
+	ranks  [nStreams64]int64 // offset of the ranks of each level
+	size   int64             // total size
+}
+
+// newLayout computes the layout of a serialized dictionary with the given
+// number of entries per level.
+func newLayout(lens [nStreams64]int) layout {
+	lt := layout{lens: lens}
+
+	off := int64(headerSize)
+	for l, n := range lens {
+		lt.chunks[l] = off
+		off += int64(n+7) &^ 7
+
+		words := 0
+		if l < nStreams64-1 {
+			words = (n + 63) >> 6
+		}
+		lt.bitArr[l] = off
+		off += 8 * int64(words)
+		lt.ranks[l] = off
+		off += 8 * int64((words+7)>>3)
+	}
+	lt.size = off
+
+	return lt
+}
+
+// putHeader encodes the header for the given flags and number of entries per
+// level.
+func putHeader(buf []byte, flags uint16, lens [nStreams64]int) {
+	copy(buf, magic)
+	binary.LittleEndian.PutUint16(buf[4:], version)
+	binary.LittleEndian.PutUint16(buf[6:], flags)
+	for l, n := range lens {
+		binary.LittleEndian.PutUint64(buf[8+8*l:], uint64(n))
+	}
+}
+
+// parseHeader decodes the header and returns the flags and the number of
+// entries per level.
+func parseHeader(buf []byte) (flags uint16, lens [nStreams64]int, err error) {
+	if string(buf[:4]) != magic {
+		return 0, lens, errors.New("dac: invalid magic string")
+	}
+	if binary.LittleEndian.Uint16(buf[4:]) != version {
+		return 0, lens, errors.New("dac: unsupported format version")
+	}
+	if flags = binary.LittleEndian.Uint16(buf[6:]); flags&^flagRMQ != 0 {
+		return 0, lens, errors.New("dac: unsupported format flags")
+	}
+
+	for l := range lens {
+		n := binary.LittleEndian.Uint64(buf[8+8*l:])
+		if n > maxEntries || n > uint64(int(^uint(0)>>1)) || (l != 0 && n > uint64(lens[l-1])) {
+			return 0, lens, errors.New("dac: invalid number of entries")
+		}
+		lens[l] = int(n)
+	}
+
+	return flags, lens, nil
+}
+
+// WriteTo writes the dictionary in serialized form to w. It returns the
+// number of bytes written and the first error encountered. The ranks are
+// computed while writing, so the dictionary does not need to be closed. The
+// RMQ index is only written when it is enabled and up to date.
+func (d *Dict) WriteTo(w io.Writer) (int64, error) {
+	cw := countWriter{w: w}
+	bw := bufio.NewWriter(&cw)
+
+	// write stops at the first error, which bw keeps
+	var err error
+	write := func(p []byte) {
+		if err == nil {
+			_, err = bw.Write(p)
+		}
+	}
+
+	var lens [nStreams64]int
+	for l := range lens {
+		lens[l] = len(d.chunks[l])
+	}
+
+	var flags uint16
+	rmq := d.rmq
+	if rmq != nil && rmq.n == lens[0] {
+		flags |= flagRMQ
+	}
+
+	var buf [headerSize]byte
+	putHeader(buf[:], flags, lens)
+	write(buf[:])
+
+	var zeros [8]byte
+	for l, n := range lens {
+		write(d.chunks[l])
+		write(zeros[:(8-n&7)&7])
+
+		if l == nStreams64-1 || err != nil {
+			break
+		}
+
+		words := (n + 63) >> 6
+		ranks := make([]int, 0, (words+7)>>3)
+		var prefix int
+		for i := 0; i < words; i++ {
+			var word uint64
+			if i < len(d.bitArr[l]) {
+				word = d.bitArr[l][i]
+			}
+			if i&7 == 0 {
+				ranks = append(ranks, prefix)
+			}
+			prefix += bits.OnesCount64(word)
+
+			binary.LittleEndian.PutUint64(buf[:], word)
+			write(buf[:8])
+		}
+
+		for _, r := range ranks {
+			binary.LittleEndian.PutUint64(buf[:], uint64(r))
+			write(buf[:8])
+		}
+	}
+
+	if flags&flagRMQ != 0 {
+		binary.LittleEndian.PutUint64(buf[:], uint64(rmq.n))
+		write(buf[:8])
+		for _, vals := range [][]uint64{rmq.min, rmq.max} {
+			for _, v := range vals {
+				binary.LittleEndian.PutUint64(buf[:], v)
+				write(buf[:8])
+			}
+		}
+		for _, args := range [][]uint8{rmq.argMin, rmq.argMax} {
+			write(args)
+			write(zeros[:(8-len(args)&7)&7])
+		}
+	}
+
+	if err == nil {
+		err = bw.Flush()
+	}
+	return cw.n, err
+}
+
+// countWriter counts the bytes written to w.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// rmqSize returns the serialized size of the RMQ index of n entries.
+func rmqSize(n int) int64 {
+	nb := int64(n+rmqBlock-1) / rmqBlock
+	return 8 + 16*nb + 2*((nb+7)&^7)
+}
+
+// ReadFrom reads a serialized dictionary from r and replaces the contents of
+// the dictionary with it. It returns the number of bytes read. Every level is
+// checked against the number of entries of the next level in the header
+// before that level is read, and the structure of the dictionary is
+// validated before it is accepted.
+func (d *Dict) ReadFrom(r io.Reader) (int64, error) {
+	var buf [headerSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	read := int64(headerSize)
+
+	flags, lens, err := parseHeader(buf[:])
+	if err != nil {
+		return read, err
+	}
+
+	// the levels are allocated as their bytes arrive, as the header is not
+	// trusted
+	c := Dict{}
+	for l, n := range lens {
+		if c.chunks[l], err = readBytes(r, n); err != nil {
+			return read, err
+		}
+		if _, err := io.ReadFull(r, buf[:(8-n&7)&7]); err != nil {
+			return read, noEOF(err)
+		}
+		read += int64(n+7) &^ 7
+
+		if l == nStreams64-1 {
+			break
+		}
+
+		words := (n + 63) >> 6
+		if c.bitArr[l], err = readU64s(r, words); err != nil {
+			return read, err
+		}
+		c.ranks[l] = make([]int, (words+7)>>3)
+		if err := readWords(r, nil, c.ranks[l]); err != nil {
+			return read, err
+		}
+		read += 8 * int64(words+len(c.ranks[l]))
+
+		// the next level is only allocated when the header matches the bits
+		if err := checkLevel(uint(l), n, c.bitArr[l], c.ranks[l], lens[l+1]); err != nil {
+			return read, err
+		}
+	}
+
+	if flags&flagRMQ != 0 {
+		rmq, err := readRMQ(r, lens[0])
+		if err != nil {
+			return read, err
+		}
+		read += rmqSize(rmq.n)
+
+		if err := rmq.check(lens[0]); err != nil {
+			return read, err
+		}
+		rmq.buildTables()
+		c.rmq = rmq
+	}
+
+	if err := c.Validate(); err != nil {
+		return read, err
+	}
+
+	// iterators over the replaced contents must notice the change
+	c.mods = d.mods + 1
+	*d = c
+	return read, nil
+}
+
+// readRMQ reads the block summaries of a serialized RMQ index from r, for a
+// dictionary of n entries.
+func readRMQ(r io.Reader, n int) (*rmqIndex, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, noEOF(err)
+	}
+	if binary.LittleEndian.Uint64(buf[:]) != uint64(n) {
+		return nil, fmt.Errorf("%w: RMQ index does not match the number of entries", ErrCorrupt)
+	}
+
+	nb := (n + rmqBlock - 1) / rmqBlock
+	rmq := rmqIndex{
+		n:      n,
+		min:    make([]uint64, nb),
+		max:    make([]uint64, nb),
+		argMin: make([]uint8, nb),
+		argMax: make([]uint8, nb),
+	}
+
+	for _, vals := range [][]uint64{rmq.min, rmq.max} {
+		if err := readWords(r, vals, nil); err != nil {
+			return nil, err
+		}
+	}
+	for _, args := range [][]uint8{rmq.argMin, rmq.argMax} {
+		if _, err := io.ReadFull(r, args); err != nil {
+			return nil, noEOF(err)
+		}
+		if _, err := io.ReadFull(r, buf[:(8-nb&7)&7]); err != nil {
+			return nil, noEOF(err)
+		}
+	}
+
+	return &rmq, nil
+}
+
+// readBytes reads n bytes from r. The bytes are allocated in steps of at
+// most readStep bytes, as they arrive.
+func readBytes(r io.Reader, n int) ([]byte, error) {
+	var p []byte
+	for len(p) < n {
+		m := n - len(p)
+		if m > readStep {
+			m = readStep
+		}
+		p = append(p, make([]byte, m)...)
+		if _, err := io.ReadFull(r, p[len(p)-m:]); err != nil {
+			return nil, noEOF(err)
+		}
+	}
+	return p, nil
+}
+
+// readU64s reads n little-endian 64-bit words from r. The words are
+// allocated in steps of at most readStep bytes, as they arrive.
+func readU64s(r io.Reader, n int) ([]uint64, error) {
+	var words []uint64
+	for len(words) < n {
+		m := n - len(words)
+		if m > readStep/8 {
+			m = readStep / 8
+		}
+		words = append(words, make([]uint64, m)...)
+		if err := readWords(r, words[len(words)-m:], nil); err != nil {
+			return nil, err
+		}
+	}
+	return words, nil
+}
+
+// noEOF turns io.EOF into io.ErrUnexpectedEOF, for input that ends after the
+// header.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// readWords reads little-endian 64-bit words from r into either words or
+// ints, whichever is not nil.
+func readWords(r io.Reader, words []uint64, ints []int) error {
+	n := len(words) + len(ints)
+
+	var buf [4096]byte
+	for i := 0; i < n; {
+		m := n - i
+		if m > len(buf)/8 {
+			m = len(buf) / 8
+		}
+		if _, err := io.ReadFull(r, buf[:8*m]); err != nil {
+			return noEOF(err)
+		}
+
+		for j := 0; j < m; j, i = j+1, i+1 {
+			w := binary.LittleEndian.Uint64(buf[8*j:])
+			if words != nil {
+				words[i] = w
+			} else {
+				ints[i] = int(w)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package dac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestWriteToReadFrom(t *testing.T) {
+	for _, n := range []int{0, 1, 63, 512, 10_000} {
+		numbers := zipfNumbers(n, 15)
+		d := From(numbers)
+
+		var buf bytes.Buffer
+		m, err := d.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != int64(buf.Len()) {
+			t.Errorf("n: %d - WriteTo returned %d, wrote %d bytes\n", n, m, buf.Len())
+		}
+		if m&7 != 0 {
+			t.Errorf("n: %d - size %d is not a multiple of 8\n", n, m)
+		}
+
+		var got Dict
+		m, err = got.ReadFrom(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != int64(newLayout(lensOf(d)).size) {
+			t.Errorf("n: %d - ReadFrom returned %d\n", n, m)
+		}
+		if !equalDict(&got, d) {
+			t.Errorf("n: %d - dictionaries differ\n", n)
+		}
+		checkDict(t, &got, numbers)
+	}
+}
+
+func TestReadFromErrors(t *testing.T) {
+	d := From(zipfNumbers(1_000, 15))
+
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	data := buf.Bytes()
+
+	var got Dict
+	if _, err := got.ReadFrom(bytes.NewReader(data[:len(data)-1])); err == nil {
+		t.Error("expected error for truncated input")
+	}
+
+	bad := append([]byte{}, data...)
+	bad[0] = 'X'
+	if _, err := got.ReadFrom(bytes.NewReader(bad)); err == nil {
+		t.Error("expected error for invalid magic string")
+	}
+
+	bad = append([]byte{}, data...)
+	bad[8+8*2] = 0xff
+	if _, err := got.ReadFrom(bytes.NewReader(bad)); err == nil {
+		t.Error("expected error for invalid number of entries")
+	}
+}
+
+func TestReadFromCorruptHeader(t *testing.T) {
+	d := From(zipfNumbers(1_000, 15))
+
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	data := buf.Bytes()
+
+	tests := []struct {
+		name string
+		n0   uint64
+		want error
+	}{
+		{"out of range", 1 << 60, nil},
+		{"negative", 1 << 63, nil},
+		{"larger than the input", 1 << 40, io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		bad := append([]byte{}, data...)
+		binary.LittleEndian.PutUint64(bad[8:], tt.n0)
+
+		var got Dict
+		_, err := got.ReadFrom(bytes.NewReader(bad))
+		if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+			t.Errorf("%s - got: %v, want: %v\n", tt.name, err, tt.want)
+		}
+	}
+
+	// a stream that ends within a level
+	var got Dict
+	if _, err := got.ReadFrom(bytes.NewReader(data[:headerSize+100])); err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated - got: %v, want: %v\n", err, io.ErrUnexpectedEOF)
+	}
+}
+
+// failWriter accepts n bytes and then fails.
+type failWriter struct {
+	n int
+}
+
+var errWrite = errors.New("write failed")
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if len(p) <= w.n {
+		w.n -= len(p)
+		return len(p), nil
+	}
+	n := w.n
+	w.n = 0
+	return n, errWrite
+}
+
+func TestWriteToError(t *testing.T) {
+	d := From(zipfNumbers(10_000, 15))
+
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+
+	for _, limit := range []int{0, 10, 5_000, buf.Len() - 1} {
+		m, err := d.WriteTo(&failWriter{n: limit})
+		if err != errWrite || m != int64(limit) {
+			t.Errorf("limit %d - got: (%d, %v), want: (%d, %v)\n", limit, m, err, limit, errWrite)
+		}
+	}
+}
+
+// lensOf returns the number of entries per level of d.
+func lensOf(d *Dict) (lens [nStreams64]int) {
+	for l := range lens {
+		lens[l] = len(d.chunks[l])
+	}
+	return
+}
@@ -0,0 +1,95 @@
+package dac
+
+import "errors"
+
+// Clone returns a deep copy of the dictionary.
+func (d *Dict) Clone() *Dict {
+	c := Dict{}
+	for l := range d.chunks {
+		c.chunks[l] = append([]byte(nil), d.chunks[l]...)
+	}
+
+	for l := range d.bitArr {
+		c.bitArr[l] = append([]uint64(nil), d.bitArr[l]...)
+		c.ranks[l] = append([]int(nil), d.ranks[l]...)
+	}
+
+	// the RMQ index is replaced, not modified, when rebuilt
+	c.rmq = d.rmq
+
+	return &c
+}
+
+// Slice returns a new, closed dictionary holding the entries in the index
+// range [lo, hi) of d. The encoded bytes and presence bits are copied level
+// by level, without decoding the values.
+func (d *Dict) Slice(lo, hi int) (*Dict, error) {
+	if lo < 0 || hi < lo || len(d.chunks[0]) < hi {
+		return nil, errors.New("dac: index range is out of bounds")
+	}
+
+	s := Dict{}
+	p, q := lo, hi
+	for l := uint(0); l < nStreams64 && p < q; l++ {
+		s.chunks[l] = append([]byte(nil), d.chunks[l][p:q]...)
+
+		if l < nStreams64-1 {
+			s.bitArr[l] = make([]uint64, (q-p+63)>>6)
+			copyBits(s.bitArr[l], 0, d.bitArr[l], p, q-p)
+			p, q = d.rankAt(l, p), d.rankAt(l, q)
+		}
+	}
+	s.Close()
+
+	return &s, nil
+}
+
+// Append appends the entries of other to the end of d. The encoded bytes of
+// every level are appended directly and the presence bits are shifted to
+// their new offset, so no values need to be decoded. Afterwards, d is closed.
+func (d *Dict) Append(other *Dict) {
+	if other == d {
+		other = d.Clone()
+	}
+
+	for l := uint(0); l < nStreams64 && len(other.chunks[l]) != 0; l++ {
+		n := len(d.chunks[l])
+		d.chunks[l] = append(d.chunks[l], other.chunks[l]...)
+
+		if l < nStreams64-1 {
+			for words := (len(d.chunks[l]) + 63) >> 6; len(d.bitArr[l]) < words; {
+				d.bitArr[l] = append(d.bitArr[l], 0)
+			}
+			copyBits(d.bitArr[l], n, other.bitArr[l], 0, len(other.chunks[l]))
+		}
+	}
+
+	for l := uint(0); l < nStreams64-1; l++ {
+		d.updateRanks(l, len(d.chunks[l])-len(other.chunks[l]))
+	}
+}
+
+// Concat constructs a new, closed dictionary holding the entries of all
+// given dictionaries, in order.
+func Concat(ds ...*Dict) *Dict {
+	var n [nStreams64]int
+	for _, d := range ds {
+		for l := range n {
+			n[l] += len(d.chunks[l])
+		}
+	}
+
+	c := Dict{}
+	for l := range c.chunks {
+		c.chunks[l] = make([]byte, 0, n[l])
+	}
+	for l := range c.bitArr {
+		c.bitArr[l] = make([]uint64, 0, (n[l]+63)>>6)
+	}
+
+	for _, d := range ds {
+		c.Append(d)
+	}
+
+	return &c
+}
@@ -0,0 +1,104 @@
+package dac
+
+import "testing"
+
+func TestClone(t *testing.T) {
+	const n = 1_000
+
+	numbers := zipfNumbers(n, 15)
+
+	d := From(numbers)
+	c := d.Clone()
+
+	if err := d.RemoveRange(0, n/2); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.UpdateU64Range(0, numbers[:n/4]); err != nil {
+		t.Fatal(err)
+	}
+
+	checkDict(t, c, numbers)
+}
+
+func TestSlice(t *testing.T) {
+	const n = 1_500
+
+	numbers := zipfNumbers(n, 15)
+	d := From(numbers)
+
+	ranges := [][2]int{{0, 0}, {0, n}, {0, 1}, {1, 65}, {63, 700}, {512, 1_024}, {n - 1, n}, {n, n}}
+	for _, r := range ranges {
+		s, err := d.Slice(r[0], r[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkDict(t, s, numbers[r[0]:r[1]])
+	}
+}
+
+func TestSliceOutOfBounds(t *testing.T) {
+	d := From(zipfNumbers(10, 15))
+
+	for _, r := range [][2]int{{5, 11}, {-1, 3}, {6, 5}} {
+		if s, err := d.Slice(r[0], r[1]); err == nil || s != nil {
+			t.Errorf("Slice(%d, %d) - expected an error\n", r[0], r[1])
+		}
+	}
+}
+
+func BenchmarkSlice(b *testing.B) {
+	const n = 10_000
+
+	d := From(zipfNumbers(n, 15))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.Slice(n/4, 3*n/4)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	numbers := zipfNumbers(1_500, 15)
+
+	for _, k := range []int{0, 1, 64, 100, 512, 1_499, 1_500} {
+		d := From(numbers[:k])
+		d.Append(From(numbers[k:]))
+		checkDict(t, d, numbers)
+	}
+
+	d := From(numbers)
+	d.Append(d)
+	checkDict(t, d, append(append([]uint64{}, numbers...), numbers...))
+}
+
+func TestConcat(t *testing.T) {
+	numbers := zipfNumbers(3_000, 15)
+
+	var ds []*Dict
+	for lo, hi := 0, 0; hi < len(numbers); lo = hi {
+		hi = lo + 37*len(ds)
+		if hi > len(numbers) {
+			hi = len(numbers)
+		}
+		ds = append(ds, From(numbers[lo:hi]))
+	}
+
+	checkDict(t, Concat(ds...), numbers)
+	checkDict(t, Concat(), nil)
+}
+
+func BenchmarkConcat(b *testing.B) {
+	const n = 10_000
+
+	numbers := zipfNumbers(n, 15)
+	ds := []*Dict{From(numbers[:n/3]), From(numbers[n/3 : 2*n/3]), From(numbers[2*n/3:])}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Concat(ds...)
+	}
+}
@@ -0,0 +1,226 @@
+package dac
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// sampleStep is the distance between the values sampled by the binary search
+// of the sorted search functions.
+const sampleStep = 64
+
+// LowerBound returns the index of the first value that is not smaller than
+// v, or Len when there is no such value. It is the position at which v
+// would be inserted to keep the dictionary sorted. The values must be in
+// ascending numerical order, see IsSorted, and the dictionary must be
+// closed.
+func (d *Dict) LowerBound(v uint64) int {
+	return d.search(func(w uint64) bool { return v <= w })
+}
+
+// UpperBound returns the index of the first value that is larger than v, or
+// Len when there is no such value. The values must be in ascending numerical
+// order and the dictionary must be closed.
+func (d *Dict) UpperBound(v uint64) int {
+	return d.search(func(w uint64) bool { return v < w })
+}
+
+// EqualRange returns the index range [lo, hi) of the values equal to v. When
+// v is not present, lo equals hi and is the insertion point of v. The values
+// must be in ascending numerical order and the dictionary must be closed.
+func (d *Dict) EqualRange(v uint64) (lo, hi int) {
+	lo = d.LowerBound(v)
+	hi = d.searchFrom(lo, func(w uint64) bool { return v < w })
+	return
+}
+
+// CountInRange returns the number of values in the value range [lo, hi). The
+// values must be in ascending numerical order and the dictionary must be
+// closed.
+func (d *Dict) CountInRange(lo, hi uint64) int {
+	if hi <= lo {
+		return 0
+	}
+
+	i := d.LowerBound(lo)
+	return d.searchFrom(i, func(w uint64) bool { return hi <= w }) - i
+}
+
+// IsSorted reports whether the values are in ascending numerical order. The
+// dictionary must be closed.
+func (d *Dict) IsSorted() bool {
+	n := len(d.chunks[0])
+	if n < 2 {
+		return true
+	}
+
+	ranks := d.ranksAt(0)
+	prev := d.decode(0, &ranks)
+	for k := 1; k < n; k++ {
+		v := d.decode(k, &ranks)
+		if v < prev {
+			return false
+		}
+		prev = v
+	}
+
+	return true
+}
+
+// Predecessor returns the index and the value of the last value that is not
+// larger than x. When there is no such value, ok is false. The values must
+// be in ascending numerical order and the dictionary must be closed. A query
+// takes O(log n) accesses.
+func (d *Dict) Predecessor(x uint64) (idx int, v uint64, ok bool) {
+	return d.predecessor(func(w uint64) bool { return x < w })
+}
+
+// Successor returns the index and the value of the first value that is not
+// smaller than x. When there is no such value, ok is false. The values must
+// be in ascending numerical order and the dictionary must be closed.
+func (d *Dict) Successor(x uint64) (idx int, v uint64, ok bool) {
+	return d.successor(func(w uint64) bool { return x <= w })
+}
+
+// PredecessorI64 is like Predecessor, for values of type int64 in ascending
+// order.
+func (d *Dict) PredecessorI64(x int64) (idx int, v int64, ok bool) {
+	idx, uv, ok := d.predecessor(func(w uint64) bool { return x < unzigzag(w) })
+	return idx, unzigzag(uv), ok
+}
+
+// SuccessorI64 is like Successor, for values of type int64 in ascending
+// order.
+func (d *Dict) SuccessorI64(x int64) (idx int, v int64, ok bool) {
+	idx, uv, ok := d.successor(func(w uint64) bool { return x <= unzigzag(w) })
+	return idx, unzigzag(uv), ok
+}
+
+// PredecessorFloat64 is like Predecessor, for values of type float64 in the
+// order of sort.Float64s, which puts NaNs first. NaNs are never returned and
+// ok is false when x is NaN.
+func (d *Dict) PredecessorFloat64(x float64) (idx int, v float64, ok bool) {
+	if math.IsNaN(x) {
+		return -1, 0, false
+	}
+
+	idx, uv, ok := d.predecessor(func(w uint64) bool { return x < unreverse64(w) })
+	if v = unreverse64(uv); ok && math.IsNaN(v) {
+		return -1, 0, false
+	}
+	return idx, v, ok
+}
+
+// SuccessorFloat64 is like Successor, for values of type float64 in the
+// order of sort.Float64s, which puts NaNs first. NaNs are never returned and
+// ok is false when x is NaN.
+func (d *Dict) SuccessorFloat64(x float64) (idx int, v float64, ok bool) {
+	if math.IsNaN(x) {
+		return -1, 0, false
+	}
+
+	// NaNs compare false, so they are skipped as part of the prefix
+	idx, uv, ok := d.successor(func(w uint64) bool { return x <= unreverse64(w) })
+	return idx, unreverse64(uv), ok
+}
+
+// PredecessorDateTime is like Predecessor, for time.Time values in
+// chronological order. When there is no such value, t is the zero time.
+func (d *Dict) PredecessorDateTime(x time.Time) (idx int, t time.Time, ok bool) {
+	idx, v, ok := d.PredecessorI64(x.UnixNano())
+	if !ok {
+		return idx, time.Time{}, false
+	}
+	return idx, time.Unix(0, v), true
+}
+
+// SuccessorDateTime is like Successor, for time.Time values in chronological
+// order. When there is no such value, t is the zero time.
+func (d *Dict) SuccessorDateTime(x time.Time) (idx int, t time.Time, ok bool) {
+	idx, v, ok := d.SuccessorI64(x.UnixNano())
+	if !ok {
+		return idx, time.Time{}, false
+	}
+	return idx, time.Unix(0, v), true
+}
+
+// predecessor returns the value before the first value for which f is true.
+func (d *Dict) predecessor(f func(v uint64) bool) (idx int, v uint64, ok bool) {
+	if idx = d.search(f) - 1; idx < 0 {
+		return -1, 0, false
+	}
+	return idx, d.at(idx), true
+}
+
+// successor returns the first value for which f is true.
+func (d *Dict) successor(f func(v uint64) bool) (idx int, v uint64, ok bool) {
+	if idx = d.search(f); idx == len(d.chunks[0]) {
+		return -1, 0, false
+	}
+	return idx, d.at(idx), true
+}
+
+// search returns the index of the first value for which f is true, or Len
+// when there is none. f must be false for a prefix of the values and true
+// for the remainder.
+func (d *Dict) search(f func(v uint64) bool) int {
+	return d.searchIn(0, len(d.chunks[0]), f)
+}
+
+// searchFrom is like search, but only considers the values from index lo
+// onwards. It gallops forward from lo, so that answers near lo are found in
+// a few steps.
+func (d *Dict) searchFrom(lo int, f func(v uint64) bool) int {
+	n := len(d.chunks[0])
+
+	// the answer lies in [a, b)
+	a, b := lo, n
+	for step := sampleStep; lo+step < n; step *= 2 {
+		if f(d.at(lo + step)) {
+			b = lo + step + 1
+			break
+		}
+		a = lo + step
+	}
+
+	return d.searchIn(a, b, f)
+}
+
+// searchIn returns the index of the first value in [lo, hi) for which f is
+// true, or hi when there is none. A binary search over every 64th value
+// selects the run of values holding the answer, which is then decoded
+// sequentially.
+func (d *Dict) searchIn(lo, hi int, f func(v uint64) bool) int {
+	if lo == hi {
+		return lo
+	}
+
+	// first sample for which f is true
+	samples := (hi - lo + sampleStep - 1) / sampleStep
+	s := sort.Search(samples, func(i int) bool {
+		return f(d.at(lo + i*sampleStep))
+	})
+	if s == 0 {
+		return lo
+	}
+
+	a, b := lo+(s-1)*sampleStep, lo+s*sampleStep
+	if hi < b {
+		b = hi
+	}
+
+	ranks := d.ranksAt(a)
+	for k := a; k < b; k++ {
+		if f(d.decode(k, &ranks)) {
+			return k
+		}
+	}
+	return b
+}
+
+// at returns the value at index k, which must be in bounds.
+func (d *Dict) at(k int) uint64 {
+	ranks := d.ranksAt(k)
+	return d.decode(k, &ranks)
+}
@@ -0,0 +1,248 @@
+package dac
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+// sortedNumbers returns n sorted values with many duplicates.
+func sortedNumbers(n int, seed int64) []uint64 {
+	numbers := zipfNumbers(n, seed)
+	for i := range numbers {
+		numbers[i] >>= 3
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	return numbers
+}
+
+func TestLowerUpperBound(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	for _, n := range []int{0, 1, 63, 64, 65, 1_000, 10_000} {
+		numbers := sortedNumbers(n, 15)
+		d := From(numbers)
+
+		queries := []uint64{0, 1, math.MaxUint64}
+		for i := 0; i < 500 && n != 0; i++ {
+			v := numbers[r.Intn(n)]
+			queries = append(queries, v, v+1, v-1)
+		}
+
+		for _, v := range queries {
+			lo := sort.Search(n, func(i int) bool { return v <= numbers[i] })
+			hi := sort.Search(n, func(i int) bool { return v < numbers[i] })
+
+			if got := d.LowerBound(v); got != lo {
+				t.Fatalf("n: %d - LowerBound(%d) - got: %d, want: %d\n", n, v, got, lo)
+			}
+			if got := d.UpperBound(v); got != hi {
+				t.Fatalf("n: %d - UpperBound(%d) - got: %d, want: %d\n", n, v, got, hi)
+			}
+			if a, b := d.EqualRange(v); a != lo || b != hi {
+				t.Fatalf("n: %d - EqualRange(%d) - got: [%d, %d), want: [%d, %d)\n", n, v, a, b, lo, hi)
+			}
+		}
+	}
+}
+
+func TestCountInRange(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	numbers := sortedNumbers(10_000, 15)
+	d := From(numbers)
+
+	for i := 0; i < 1_000; i++ {
+		lo, hi := numbers[r.Intn(len(numbers))], numbers[r.Intn(len(numbers))]
+		if i&1 == 0 {
+			hi = lo + uint64(r.Intn(100))
+		}
+
+		var want int
+		for _, v := range numbers {
+			if lo <= v && v < hi {
+				want++
+			}
+		}
+
+		if got := d.CountInRange(lo, hi); got != want {
+			t.Fatalf("CountInRange(%d, %d) - got: %d, want: %d\n", lo, hi, got, want)
+		}
+	}
+
+	if got := d.CountInRange(0, math.MaxUint64); got != len(numbers) {
+		t.Errorf("CountInRange over all values - got: %d, want: %d\n", got, len(numbers))
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	numbers := sortedNumbers(1_000, 15)
+	if d := From(numbers); !d.IsSorted() {
+		t.Error("sorted values reported as unsorted")
+	}
+	if d := From(nil); !d.IsSorted() {
+		t.Error("empty dictionary reported as unsorted")
+	}
+
+	numbers[500], numbers[900] = numbers[900]+1, numbers[500]
+	if d := From(numbers); d.IsSorted() {
+		t.Error("unsorted values reported as sorted")
+	}
+}
+
+func BenchmarkLowerBound(b *testing.B) {
+	const n = 100_000
+
+	numbers := sortedNumbers(n, 15)
+	d := From(numbers)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.LowerBound(numbers[i%n])
+	}
+}
+
+func TestPredecessorSuccessor(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	for _, n := range []int{0, 1, 100, 10_000} {
+		numbers := sortedNumbers(n, 15)
+		d := From(numbers)
+
+		queries := []uint64{0, math.MaxUint64}
+		for i := 0; i < 500 && n != 0; i++ {
+			v := numbers[r.Intn(n)]
+			queries = append(queries, v, v+1, v-1)
+		}
+
+		for _, x := range queries {
+			p := sort.Search(n, func(i int) bool { return x < numbers[i] }) - 1
+			idx, v, ok := d.Predecessor(x)
+			if ok != (p >= 0) || (ok && (idx != p || v != numbers[p])) {
+				t.Fatalf("n: %d - Predecessor(%d) - got: (%d, %d, %v), want index: %d\n", n, x, idx, v, ok, p)
+			}
+
+			s := sort.Search(n, func(i int) bool { return x <= numbers[i] })
+			idx, v, ok = d.Successor(x)
+			if ok != (s < n) || (ok && (idx != s || v != numbers[s])) {
+				t.Fatalf("n: %d - Successor(%d) - got: (%d, %d, %v), want index: %d\n", n, x, idx, v, ok, s)
+			}
+		}
+	}
+}
+
+func TestPredecessorSuccessorI64(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	numbers := make([]int64, 5_000)
+	for i := range numbers {
+		numbers[i] = r.Int63n(1<<40) - 1<<39
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	d, _ := New()
+	d.WriteI64List(numbers)
+	d.Close()
+
+	for i := 0; i < 1_000; i++ {
+		x := r.Int63n(1<<41) - 1<<40
+
+		p := sort.Search(len(numbers), func(i int) bool { return x < numbers[i] }) - 1
+		idx, v, ok := d.PredecessorI64(x)
+		if ok != (p >= 0) || (ok && (idx != p || v != numbers[p])) {
+			t.Fatalf("PredecessorI64(%d) - got: (%d, %d, %v), want index: %d\n", x, idx, v, ok, p)
+		}
+
+		s := sort.Search(len(numbers), func(i int) bool { return x <= numbers[i] })
+		idx, v, ok = d.SuccessorI64(x)
+		if ok != (s < len(numbers)) || (ok && (idx != s || v != numbers[s])) {
+			t.Fatalf("SuccessorI64(%d) - got: (%d, %d, %v), want index: %d\n", x, idx, v, ok, s)
+		}
+	}
+}
+
+func TestPredecessorSuccessorFloat64(t *testing.T) {
+	r := rand.New(rand.NewSource(15))
+
+	numbers := make([]float64, 2_000)
+	for i := range numbers {
+		numbers[i] = r.NormFloat64()
+	}
+	numbers[0], numbers[1] = math.NaN(), math.NaN()
+	sort.Float64s(numbers)
+
+	d, _ := New()
+	d.WriteFloat64List(numbers)
+	d.Close()
+
+	for i := 0; i < 1_000; i++ {
+		x := r.NormFloat64()
+
+		// the values after the NaNs
+		vals := numbers[2:]
+
+		p := sort.Search(len(vals), func(i int) bool { return x < vals[i] }) - 1
+		idx, v, ok := d.PredecessorFloat64(x)
+		if ok != (p >= 0) || (ok && (idx != p+2 || v != vals[p])) {
+			t.Fatalf("PredecessorFloat64(%v) - got: (%d, %v, %v), want index: %d\n", x, idx, v, ok, p+2)
+		}
+
+		s := sort.Search(len(vals), func(i int) bool { return x <= vals[i] })
+		idx, v, ok = d.SuccessorFloat64(x)
+		if ok != (s < len(vals)) || (ok && (idx != s+2 || v != vals[s])) {
+			t.Fatalf("SuccessorFloat64(%v) - got: (%d, %v, %v), want index: %d\n", x, idx, v, ok, s+2)
+		}
+	}
+
+	if _, _, ok := d.PredecessorFloat64(math.Inf(-1)); ok {
+		t.Error("PredecessorFloat64 returned a NaN")
+	}
+	if _, _, ok := d.SuccessorFloat64(math.NaN()); ok {
+		t.Error("SuccessorFloat64 of NaN succeeded")
+	}
+}
+
+func TestPredecessorSuccessorDateTime(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	times := make([]time.Time, 1_000)
+	for i := range times {
+		times[i] = base.Add(time.Duration(i) * time.Hour)
+	}
+
+	d, _ := New()
+	d.WriteDateTimeList(times)
+	d.Close()
+
+	x := base.Add(100*time.Hour + time.Minute)
+	if idx, tm, ok := d.PredecessorDateTime(x); !ok || idx != 100 || !tm.Equal(times[100]) {
+		t.Errorf("PredecessorDateTime - got: (%d, %v, %v)\n", idx, tm, ok)
+	}
+	if idx, tm, ok := d.SuccessorDateTime(x); !ok || idx != 101 || !tm.Equal(times[101]) {
+		t.Errorf("SuccessorDateTime - got: (%d, %v, %v)\n", idx, tm, ok)
+	}
+	if idx, tm, ok := d.PredecessorDateTime(base.Add(-time.Second)); ok || idx != -1 || !tm.IsZero() {
+		t.Errorf("PredecessorDateTime before the first value - got: (%d, %v, %v)\n", idx, tm, ok)
+	}
+	if idx, tm, ok := d.SuccessorDateTime(times[999].Add(time.Second)); ok || idx != -1 || !tm.IsZero() {
+		t.Errorf("SuccessorDateTime after the last value - got: (%d, %v, %v)\n", idx, tm, ok)
+	}
+}
+
+func BenchmarkSuccessor(b *testing.B) {
+	const n = 100_000
+
+	numbers := sortedNumbers(n, 15)
+	d := From(numbers)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.Successor(numbers[i%n] + 1)
+	}
+}
@@ -0,0 +1,52 @@
+package dac
+
+import "unsafe"
+
+// Stats describes the space use and the shape of a dictionary.
+type Stats struct {
+	Len              int                 // number of entries
+	ChunkBytes       [nStreams64]int     // bytes in use per level
+	BitmapBytes      [nStreams64 - 1]int // presence bit bytes in use per level
+	RankBytes        [nStreams64 - 1]int // rank bytes in use per level
+	ByteLengths      [nStreams64]int     // number of values of 1, 2, ..., 8 bytes
+	UsedBytes        int                 // total bytes in use
+	AllocatedBytes   int                 // total bytes allocated
+	BitsPerValue     float64             // bits in use per value
+	CompressionRatio float64             // plain 8-byte values versus bytes in use
+}
+
+// Stats returns the space use and the shape of the dictionary. The values are
+// not decoded. The dictionary should be closed, so that the rank bytes are
+// accounted for.
+func (d *Dict) Stats() Stats {
+	const wordSize, intSize = int(unsafe.Sizeof(uint64(0))), int(unsafe.Sizeof(int(0)))
+
+	s := Stats{Len: len(d.chunks[0])}
+	for l := range d.chunks {
+		s.ChunkBytes[l] = len(d.chunks[l])
+		s.UsedBytes += len(d.chunks[l])
+		s.AllocatedBytes += cap(d.chunks[l])
+
+		// values of l+1 bytes have their last entry at level l
+		s.ByteLengths[l] = len(d.chunks[l])
+		if l < nStreams64-1 {
+			s.ByteLengths[l] -= len(d.chunks[l+1])
+		}
+	}
+
+	for l := range d.bitArr {
+		s.BitmapBytes[l] = wordSize * len(d.bitArr[l])
+		s.RankBytes[l] = intSize * len(d.ranks[l])
+		s.UsedBytes += s.BitmapBytes[l] + s.RankBytes[l]
+		s.AllocatedBytes += wordSize*cap(d.bitArr[l]) + intSize*cap(d.ranks[l])
+	}
+
+	if s.Len != 0 {
+		s.BitsPerValue = float64(8*s.UsedBytes) / float64(s.Len)
+	}
+	if s.UsedBytes != 0 {
+		s.CompressionRatio = float64(8*s.Len) / float64(s.UsedBytes)
+	}
+
+	return s
+}
@@ -0,0 +1,52 @@
+package dac
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	numbers := []uint64{1, 2, 300, 70_000, 1 << 40, 1 << 63}
+
+	d, err := New(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.WriteU64List(numbers)
+	d.Close()
+
+	s := d.Stats()
+	if s.Len != len(numbers) {
+		t.Errorf("Len - got: %d, want: %d\n", s.Len, len(numbers))
+	}
+
+	wantChunks := [nStreams64]int{6, 4, 3, 2, 2, 2, 1, 1}
+	if s.ChunkBytes != wantChunks {
+		t.Errorf("ChunkBytes - got: %v, want: %v\n", s.ChunkBytes, wantChunks)
+	}
+
+	wantLengths := [nStreams64]int{2, 1, 1, 0, 0, 1, 0, 1}
+	if s.ByteLengths != wantLengths {
+		t.Errorf("ByteLengths - got: %v, want: %v\n", s.ByteLengths, wantLengths)
+	}
+
+	for l := range s.BitmapBytes {
+		if s.BitmapBytes[l] != 8 || s.RankBytes[l] != 8 {
+			t.Errorf("level %d - BitmapBytes: %d, RankBytes: %d\n", l, s.BitmapBytes[l], s.RankBytes[l])
+		}
+	}
+
+	if want := 21 + 7*16; s.UsedBytes != want {
+		t.Errorf("UsedBytes - got: %d, want: %d\n", s.UsedBytes, want)
+	}
+	if s.AllocatedBytes < s.UsedBytes {
+		t.Errorf("AllocatedBytes %d < UsedBytes %d\n", s.AllocatedBytes, s.UsedBytes)
+	}
+	if want := float64(8*len(numbers)) / float64(s.UsedBytes); s.CompressionRatio != want {
+		t.Errorf("CompressionRatio - got: %v, want: %v\n", s.CompressionRatio, want)
+	}
+	if want := float64(8*s.UsedBytes) / float64(len(numbers)); s.BitsPerValue != want {
+		t.Errorf("BitsPerValue - got: %v, want: %v\n", s.BitsPerValue, want)
+	}
+
+	if s := (&Dict{}).Stats(); s.BitsPerValue != 0 || s.CompressionRatio != 0 {
+		t.Errorf("empty dictionary - got: %+v\n", s)
+	}
+}
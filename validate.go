@@ -0,0 +1,67 @@
+package dac
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// ErrCorrupt is returned when the structure of a dictionary is inconsistent.
+var ErrCorrupt = errors.New("dac: corrupt dictionary")
+
+// Validate checks the structural integrity of the dictionary. For every
+// level that can continue to a next level, it verifies that the bit array has
+// the right length, that no bits are set beyond the last entry, that the
+// number of bits set equals the number of entries of the next level and
+// that the ranks match a fresh prefix count. The last level has no bit
+// array, so its entries can never continue. Finally, the last byte of every
+// value of more than one byte must be non-zero, as values are encoded in
+// their shortest form. The dictionary must be closed. The returned error
+// wraps ErrCorrupt.
+func (d *Dict) Validate() error {
+	for l := uint(0); l < nStreams64-1; l++ {
+		if err := checkLevel(l, len(d.chunks[l]), d.bitArr[l], d.ranks[l], len(d.chunks[l+1])); err != nil {
+			return err
+		}
+	}
+
+	for l := uint(1); l < nStreams64; l++ {
+		for k, c := range d.chunks[l] {
+			if c == 0 && (l == nStreams64-1 || !d.bit(l, k)) {
+				return fmt.Errorf("%w: level %d ends a value with a zero byte at index %d", ErrCorrupt, l, k)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkLevel verifies the bit array and the ranks of level l, which holds n
+// entries, against the number of entries next of level l+1. It needs no
+// entries of level l+1, so that a serialized level can be checked before
+// the next one is read.
+func checkLevel(l uint, n int, arr []uint64, ranks []int, next int) error {
+	if words := (n + 63) >> 6; len(arr) != words {
+		return fmt.Errorf("%w: level %d holds %d bit words for %d entries", ErrCorrupt, l, len(arr), n)
+	}
+	if n&63 != 0 && arr[len(arr)-1]>>(n&63) != 0 {
+		return fmt.Errorf("%w: level %d has bits set beyond its %d entries", ErrCorrupt, l, n)
+	}
+
+	if len(ranks) < (len(arr)+7)>>3 {
+		return fmt.Errorf("%w: level %d holds too few ranks", ErrCorrupt, l)
+	}
+
+	var prefix int
+	for j, w := range arr {
+		if j&7 == 0 && ranks[j>>3] != prefix {
+			return fmt.Errorf("%w: level %d has an invalid rank for block %d", ErrCorrupt, l, j>>3)
+		}
+		prefix += bits.OnesCount64(w)
+	}
+
+	if prefix != next {
+		return fmt.Errorf("%w: level %d has %d bits set for %d entries at level %d", ErrCorrupt, l, prefix, next, l+1)
+	}
+	return nil
+}
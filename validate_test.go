@@ -0,0 +1,148 @@
+package dac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestValidateMutations(t *testing.T) {
+	const n = 2_000
+
+	numbers := zipfNumbers(n, 15)
+	values := zipfNumbers(n, 16)
+	r := rand.New(rand.NewSource(15))
+
+	d := From(numbers)
+	want := append([]uint64{}, numbers...)
+
+	for i, v := range values {
+		k := r.Intn(len(want))
+		switch i % 3 {
+		case 0:
+			d.InsertU64At(k, v)
+			want = append(want[:k], append([]uint64{v}, want[k:]...)...)
+		case 1:
+			d.RemoveAt(k)
+			want = append(want[:k], want[k+1:]...)
+		case 2:
+			d.UpdateU64At(k, v)
+			want[k] = v
+		}
+
+		if err := d.Validate(); err != nil {
+			t.Fatalf("operation %d: %s\n", i, err)
+		}
+	}
+
+	checkDict(t, d, want)
+}
+
+func TestValidateCorrupt(t *testing.T) {
+	numbers := zipfNumbers(2_000, 15)
+
+	corruptions := map[string]func(d *Dict){
+		"flipped bit":       func(d *Dict) { d.bitArr[0][3] ^= 1 << 5 },
+		"bit beyond length": func(d *Dict) { d.bitArr[1][len(d.bitArr[1])-1] |= 1 << 63 },
+		"invalid rank":      func(d *Dict) { d.ranks[0][1]++ },
+		"missing ranks":     func(d *Dict) { d.ranks[0] = d.ranks[0][:0] },
+		"short bit array":   func(d *Dict) { d.bitArr[0] = d.bitArr[0][:1] },
+		"zero last byte":    func(d *Dict) { d.chunks[nStreams64-1][0] = 0 },
+		"lost entry":        func(d *Dict) { d.chunks[2] = d.chunks[2][1:] },
+	}
+
+	for name, corrupt := range corruptions {
+		d := From(numbers)
+		if err := d.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		corrupt(d)
+		if err := d.Validate(); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s - got: %v, want: %v\n", name, err, ErrCorrupt)
+		}
+	}
+}
+
+func TestValidateUnclosed(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.WriteU64List(zipfNumbers(2_000, 15))
+
+	if err := d.Validate(); err == nil {
+		t.Error("expected error for unclosed dictionary")
+	}
+	d.Close()
+	if err := d.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateWriteLists(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.WriteBoolList(make([]bool, 100))
+	d.WriteI8List(make([]int8, 100))
+	d.Close()
+
+	if err := d.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReadFromCorrupt(t *testing.T) {
+	d := From(zipfNumbers(2_000, 15))
+	d.bitArr[0][3] ^= 1 << 5
+
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+
+	var got Dict
+	if _, err := got.ReadFrom(&buf); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("got: %v, want: %v\n", err, ErrCorrupt)
+	}
+}
+
+func TestReadFromCorruptLevelCount(t *testing.T) {
+	d := From(zipfNumbers(2_000, 15))
+	lens := lensOf(d)
+
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	data := buf.Bytes()
+
+	// every level count that does not match the bits of the level before
+	for l := 1; l < nStreams64 && lens[l] != 0; l++ {
+		for _, n := range []int{lens[l] - 1, lens[l] + 1, lens[l-1]} {
+			if n == lens[l] || lens[l-1] < n {
+				continue
+			}
+			bad := append([]byte{}, data...)
+			binary.LittleEndian.PutUint64(bad[8+8*l:], uint64(n))
+
+			var got Dict
+			if _, err := got.ReadFrom(bytes.NewReader(bad)); !errors.Is(err, ErrCorrupt) {
+				t.Errorf("level %d with %d entries - got: %v, want: %v\n", l, n, err, ErrCorrupt)
+			}
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	const n = 100_000
+
+	d := From(zipfNumbers(n, 15))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.Validate()
+	}
+}